@@ -0,0 +1,166 @@
+package sdk
+
+import (
+	"context"
+	"time"
+
+	wapc "github.com/wapc/wapc-guest-tinygo"
+)
+
+// PingFunction is the capability function invoked by health checks.
+const PingFunction = "ping"
+
+// HostCallEvent describes a completed host call reported to an Observer.
+type HostCallEvent struct {
+	// Namespace is the namespace the call was routed to.
+	Namespace string
+
+	// Capability is the host capability, such as "kvstore".
+	Capability string
+
+	// Function is the capability function, such as "get".
+	Function string
+
+	// Request is the payload sent to the host.
+	Request []byte
+
+	// Response is the payload returned by the host, if any.
+	Response []byte
+
+	// Err is the error returned by the host call or its context.
+	Err error
+
+	// RequestID is the RuntimeConfig.RequestID the call was made under.
+	RequestID string
+}
+
+// Observer receives every host call made through RuntimeConfig.Call. It runs
+// synchronously on the calling goroutine and must not modify the payloads.
+type Observer func(HostCallEvent)
+
+// Call issues a host call on behalf of a capability client. The call is bounded
+// by the request context and, when positive, by timeout. Failures are reported
+// to Logger and every call is reported to Observer when they are set.
+func (c RuntimeConfig) Call(
+	hostCall func(string, string, string, []byte) ([]byte, error),
+	timeout time.Duration,
+	capability, function string,
+	payload []byte,
+) ([]byte, error) {
+	ctx := c.Context()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	resp, err := CallContext(ctx, hostCall, c.Namespace, capability, function, payload)
+	if err != nil {
+		c.Debugf("%s/%s: host call failed: %v", capability, function, err)
+	}
+
+	if c.Observer != nil {
+		c.Observer(HostCallEvent{
+			Namespace:  c.Namespace,
+			Capability: capability,
+			Function:   function,
+			Request:    payload,
+			Response:   resp,
+			Err:        err,
+			RequestID:  c.RequestID,
+		})
+	}
+
+	return resp, err
+}
+
+// Ping issues an empty PingFunction call to capability, bounded by
+// DefaultTimeout, and returns an error wrapping ErrHostCall if the host does not
+// answer. Capability clients also decode the returned status in their own Ping.
+func (c RuntimeConfig) Ping(hostCall func(string, string, string, []byte) ([]byte, error), capability string) error {
+	if _, err := c.Call(hostCall, c.DefaultTimeout, capability, PingFunction, nil); err != nil {
+		return HostCallError(err)
+	}
+
+	return nil
+}
+
+// RawCall invokes function on capability through the waPC host with payload
+// passed through unchanged. It is an escape hatch for capabilities the SDK does
+// not model yet; see RuntimeConfig.RawCall.
+func RawCall(rt RuntimeConfig, capability, function string, payload []byte) ([]byte, error) {
+	return rt.RawCall(wapc.HostCall, capability, function, payload)
+}
+
+// RawCall invokes function on capability through hostCall, bounded by
+// DefaultTimeout, and returns the host's response without interpreting it.
+// Transport failures return an error wrapping ErrHostCall; decoding the
+// response and checking any status it carries is left to the caller.
+func (c RuntimeConfig) RawCall(
+	hostCall func(string, string, string, []byte) ([]byte, error),
+	capability, function string,
+	payload []byte,
+) ([]byte, error) {
+	resp, err := c.Call(hostCall, c.DefaultTimeout, capability, function, payload)
+	if err != nil {
+		return nil, HostCallError(err)
+	}
+
+	return resp, nil
+}
+
+// CallContext invokes hostCall and returns early with the context error if ctx
+// is done before the host responds. A call that has already completed when ctx
+// is done returns its result rather than the context error.
+//
+// waPC host calls cannot be interrupted. Where goroutines run in parallel an
+// abandoned call finishes on its own and its result is discarded; on
+// single-threaded wasm the host import blocks the only thread, so the bound
+// only takes effect once the call returns and cannot preempt it. Contexts that
+// can never be canceled invoke hostCall directly without starting a goroutine.
+// A nil hostCall returns ErrNilHostCall rather than panicking.
+func CallContext(
+	ctx context.Context,
+	hostCall func(string, string, string, []byte) ([]byte, error),
+	namespace, capability, function string,
+	payload []byte,
+) ([]byte, error) {
+	if hostCall == nil {
+		return nil, ErrNilHostCall
+	}
+
+	// Skip the goroutine entirely when there is nothing to wait on.
+	if ctx.Done() == nil {
+		return hostCall(namespace, capability, function, payload)
+	}
+
+	// Avoid issuing a host call that the caller has already given up on.
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	type result struct {
+		resp []byte
+		err  error
+	}
+
+	// Buffer the channel so the host call goroutine never blocks after a timeout.
+	done := make(chan result, 1)
+	go func() {
+		resp, err := hostCall(namespace, capability, function, payload)
+		done <- result{resp: resp, err: err}
+	}()
+
+	select {
+	case r := <-done:
+		return r.resp, r.err
+	case <-ctx.Done():
+		// Both cases can be ready at once; prefer a result that already arrived.
+		select {
+		case r := <-done:
+			return r.resp, r.err
+		default:
+			return nil, ctx.Err()
+		}
+	}
+}
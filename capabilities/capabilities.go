@@ -0,0 +1,74 @@
+package capabilities
+
+import (
+	sdk "github.com/tarmac-project/sdk"
+	"github.com/tarmac-project/sdk/function"
+	"github.com/tarmac-project/sdk/httpclient"
+	"github.com/tarmac-project/sdk/kv"
+	"github.com/tarmac-project/sdk/logging"
+	"github.com/tarmac-project/sdk/metrics"
+	"github.com/tarmac-project/sdk/sql"
+)
+
+// HostCall defines the waPC host function signature shared by every client.
+type HostCall func(string, string, string, []byte) ([]byte, error)
+
+// Config controls how capability clients are constructed.
+type Config struct {
+	// SDKConfig provides the runtime configuration shared by every client.
+	SDKConfig sdk.RuntimeConfig
+
+	// HostCall overrides the waPC host function used by every client.
+	HostCall HostCall
+}
+
+// Clients constructs capability clients that share one runtime configuration
+// and host call.
+type Clients struct {
+	runtime  sdk.RuntimeConfig
+	hostCall HostCall
+}
+
+// New creates a Clients factory with namespace defaults applied once for all
+// clients.
+func New(config Config) (*Clients, error) {
+	runtime := config.SDKConfig
+	if runtime.Namespace == "" {
+		runtime.Namespace = sdk.DefaultNamespace
+	}
+
+	return &Clients{runtime: runtime, hostCall: config.HostCall}, nil
+}
+
+// Config returns the runtime configuration shared by every client.
+func (c *Clients) Config() sdk.RuntimeConfig { return c.runtime }
+
+// HTTP returns an HTTP client using the shared configuration.
+func (c *Clients) HTTP() (*httpclient.HTTPClient, error) {
+	return httpclient.New(httpclient.Config{SDKConfig: c.runtime, HostCall: c.hostCall})
+}
+
+// KV returns a key-value client using the shared configuration.
+func (c *Clients) KV() (*kv.StoreClient, error) {
+	return kv.New(kv.Config{SDKConfig: c.runtime, HostCall: c.hostCall})
+}
+
+// SQL returns a SQL client using the shared configuration.
+func (c *Clients) SQL() (*sql.DBClient, error) {
+	return sql.New(sql.Config{SDKConfig: c.runtime, HostCall: sql.HostCall(c.hostCall)})
+}
+
+// Metrics returns a metrics client using the shared configuration.
+func (c *Clients) Metrics() (*metrics.HostMetrics, error) {
+	return metrics.New(metrics.Config{SDKConfig: c.runtime, HostCall: metrics.HostCall(c.hostCall)})
+}
+
+// Logging returns a logging client using the shared configuration.
+func (c *Clients) Logging() (*logging.HostLogger, error) {
+	return logging.New(logging.Config{SDKConfig: c.runtime, HostCall: c.hostCall})
+}
+
+// Function returns a function-to-function client using the shared configuration.
+func (c *Clients) Function() (*function.HostFunction, error) {
+	return function.New(function.Config{SDKConfig: c.runtime, HostCall: function.HostCall(c.hostCall)})
+}
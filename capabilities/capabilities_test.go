@@ -0,0 +1,96 @@
+package capabilities
+
+import (
+	"testing"
+
+	sdk "github.com/tarmac-project/sdk"
+	"github.com/tarmac-project/sdk/hostmock"
+)
+
+func TestClientsShareNamespace(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		name      string
+		runtime   sdk.RuntimeConfig
+		namespace string
+	}{
+		{name: "custom namespace", runtime: sdk.RuntimeConfig{Namespace: "custom"}, namespace: "custom"},
+		{name: "default namespace", namespace: sdk.DefaultNamespace},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			mock, err := hostmock.New(hostmock.Config{ExpectedNamespace: tc.namespace})
+			if err != nil {
+				t.Fatalf("failed to create hostmock: %v", err)
+			}
+
+			clients, err := New(Config{SDKConfig: tc.runtime, HostCall: mock.HostCall})
+			if err != nil {
+				t.Fatalf("New returned error: %v", err)
+			}
+			if got := clients.Config().Namespace; got != tc.namespace {
+				t.Fatalf("namespace mismatch: want %q, got %q", tc.namespace, got)
+			}
+
+			// Responses are irrelevant here; only the routing of each call is checked.
+			httpClient, err := clients.HTTP()
+			if err != nil {
+				t.Fatalf("HTTP returned error: %v", err)
+			}
+			_, _ = httpClient.Get("http://example.com")
+
+			kvClient, err := clients.KV()
+			if err != nil {
+				t.Fatalf("KV returned error: %v", err)
+			}
+			_, _ = kvClient.Get("key")
+
+			sqlClient, err := clients.SQL()
+			if err != nil {
+				t.Fatalf("SQL returned error: %v", err)
+			}
+			_, _ = sqlClient.Exec("SELECT 1")
+
+			metricsClient, err := clients.Metrics()
+			if err != nil {
+				t.Fatalf("Metrics returned error: %v", err)
+			}
+			counter, err := metricsClient.NewCounter("requests_total")
+			if err != nil {
+				t.Fatalf("NewCounter returned error: %v", err)
+			}
+			counter.Inc()
+
+			logger, err := clients.Logging()
+			if err != nil {
+				t.Fatalf("Logging returned error: %v", err)
+			}
+			logger.Info("message")
+
+			functionClient, err := clients.Function()
+			if err != nil {
+				t.Fatalf("Function returned error: %v", err)
+			}
+			_, _ = functionClient.Call("target", nil)
+
+			calls := mock.Calls()
+			capabilities := make(map[string]bool, len(calls))
+			for _, call := range calls {
+				if call.Namespace != tc.namespace {
+					t.Fatalf("%s call used namespace %q, want %q", call.Capability, call.Namespace, tc.namespace)
+				}
+				capabilities[call.Capability] = true
+			}
+
+			for _, want := range []string{"httpclient", "kvstore", "sql", "metrics", "logging", "function"} {
+				if !capabilities[want] {
+					t.Fatalf("expected a %s host call, got %+v", want, calls)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,13 @@
+/*
+Package capabilities builds every Tarmac capability client from one shared
+runtime configuration.
+
+Creating clients one package at a time means repeating the namespace and host
+call for each. Clients wires the HTTP, key-value, SQL, metrics, logging, and
+function clients from a single sdk.RuntimeConfig, typically taken from
+sdk.SDK.Config, so namespacing, timeouts, and loggers stay consistent.
+
+Clients that need package-specific options, such as a metric prefix, can still
+be created with the package's own New function.
+*/
+package capabilities
@@ -0,0 +1,20 @@
+module github.com/tarmac-project/sdk/capabilities
+
+go 1.23
+
+require (
+	github.com/tarmac-project/sdk v0.2.0
+	github.com/tarmac-project/sdk/function v0.2.0
+	github.com/tarmac-project/sdk/hostmock v0.1.1
+	github.com/tarmac-project/sdk/httpclient v0.2.0
+	github.com/tarmac-project/sdk/kv v0.2.0
+	github.com/tarmac-project/sdk/logging v0.2.0
+	github.com/tarmac-project/sdk/metrics v0.2.0
+	github.com/tarmac-project/sdk/sql v0.2.0
+)
+
+require (
+	github.com/aperturerobotics/protobuf-go-lite v0.11.0 // indirect
+	github.com/tarmac-project/protobuf-go v0.1.0 // indirect
+	github.com/wapc/wapc-guest-tinygo v0.3.3 // indirect
+)
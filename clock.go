@@ -0,0 +1,32 @@
+package sdk
+
+import "time"
+
+// SystemClock is the Clock backed by the time package. It is the default
+// wherever a Clock can be supplied.
+var SystemClock Clock = systemClock{}
+
+// Clock supplies the current time and timers to time-dependent code, so tests
+// can inject a fake clock and advance time deterministically instead of
+// sleeping.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+
+	// After returns a channel that receives the current time once d has
+	// elapsed.
+	After(d time.Duration) <-chan time.Time
+}
+
+// systemClock implements Clock with the time package.
+type systemClock struct{}
+
+// Now returns time.Now.
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// After returns time.After(d).
+func (systemClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
@@ -0,0 +1,55 @@
+package sdk
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+const (
+	// DiscoveryCapability is the host capability that answers capability discovery.
+	DiscoveryCapability = "discovery"
+
+	// CapabilitiesFunction is the DiscoveryCapability function that lists host capabilities.
+	CapabilitiesFunction = "capabilities"
+)
+
+// ErrDiscoveryUnsupported indicates the host does not implement capability
+// discovery, so support for any capability is unknown.
+var ErrDiscoveryUnsupported = errors.New("host does not support capability discovery")
+
+// Capability describes a capability the host reports as supported.
+type Capability struct {
+	// Name is the capability name used for host calls, such as "kvstore".
+	Name string `json:"name"`
+
+	// Version is the host's version of the capability, if it reports one.
+	Version string `json:"version,omitempty"`
+}
+
+// Capabilities asks the host which capabilities it supports by calling
+// CapabilitiesFunction on DiscoveryCapability, bounded by DefaultTimeout. The
+// host answers with a JSON array of Capability objects.
+//
+// Hosts that predate discovery reject the call or answer with an empty payload;
+// both return an error wrapping ErrDiscoveryUnsupported. Callers should treat
+// that as "unknown" rather than "unsupported" and fall back to attempting the
+// capability call directly. A payload that is not a valid list returns
+// ErrHostResponseInvalid.
+func (c RuntimeConfig) Capabilities(
+	hostCall func(string, string, string, []byte) ([]byte, error),
+) ([]Capability, error) {
+	resp, err := c.Call(hostCall, c.DefaultTimeout, DiscoveryCapability, CapabilitiesFunction, nil)
+	if err != nil {
+		return nil, errors.Join(ErrDiscoveryUnsupported, ErrHostCall, err)
+	}
+	if len(resp) == 0 {
+		return nil, ErrDiscoveryUnsupported
+	}
+
+	var capabilities []Capability
+	if err := json.Unmarshal(resp, &capabilities); err != nil {
+		return nil, errors.Join(ErrHostResponseInvalid, err)
+	}
+
+	return capabilities, nil
+}
@@ -4,6 +4,57 @@ building Tarmac WebAssembly functions.
 
 The package exposes New to register a waPC handler and a RuntimeConfig that is
 shared by capability clients (e.g., HTTP). DefaultNamespace is used when a
-namespace is not explicitly provided.
+namespace is not explicitly provided. SDK.Handle registers additional named
+entry points alongside the main handler. Config.RecoverPanics opts in to
+converting handler panics into a *PanicError return, and Config.Middleware wraps
+every registered handler for cross-cutting concerns such as logging or timing.
+
+Config.HandlerTimeout sets a soft time budget for every registered handler.
+WASM cannot preempt a running handler, so the budget is cooperative: a
+Config.ContextHandler receives a context that is cancelled when the budget
+elapses, and any handler that returns after that fails with ErrHandlerTimeout.
+
+Config.DefaultTimeout, Config.Logger, and Config.Observer are carried in
+RuntimeConfig and shared by every client built from it. DefaultTimeout bounds
+each host call unless a client's Config.Timeout overrides it, Logger receives
+diagnostics such as failed host calls, and Observer sees the request and
+response of every host call. RuntimeConfig.Call applies all three and is what
+capability clients use to reach the host.
+
+RuntimeConfig.RequestID correlates the host calls made while serving one
+invocation. The waPC host call carries no metadata, so it is reported to
+Observer in HostCallEvent.RequestID and the HTTP client forwards it as the
+X-Request-Id header; other capability payloads have no field to carry it.
+
+WithRequestContext attaches a request-scoped context to a RuntimeConfig so
+clients built from it stop waiting on the host once the request is cancelled or
+its deadline passes. CallContext applies a context to a single host call and
+returns ErrNilHostCall rather than panicking when hostCall is nil, as it is in
+a client not built with New. StatusToError maps host status codes to the shared
+error sentinels, and MarshalRequest wraps request encoding failures in
+ErrMarshalRequest for every client. RuntimeConfig.Ping issues an empty
+PingFunction call as a cheap liveness probe; the kv, sql, and httpclient
+clients expose their own Ping that also checks the returned status.
+
+An empty response from the host is never mistaken for success: the kv, sql, and
+httpclient clients return an error wrapping ErrHostResponseInvalid when the
+host answers with no payload. The function client is the exception for Call,
+where an empty output is what a function that returns nothing produces.
+
+Capability clients classify failed host calls with HostCallError: transport
+failures wrap ErrHostCall, while cancellation and deadline errors match only
+the context error, so errors.Is gives the same answer whichever client failed.
+
+RawCall reaches a host capability the SDK does not model yet. It passes the
+payload through unchanged and wraps transport failures in ErrHostCall, leaving
+the response format to the caller.
+
+Clock abstracts the current time and timers for time-dependent helpers such as
+the httpclient circuit breaker and cookie jar; SystemClock is the default, and
+tests can inject a fake to advance time deterministically.
+
+RuntimeConfig.Capabilities lets a function feature-detect host support before
+relying on a capability. Hosts without discovery return an error wrapping
+ErrDiscoveryUnsupported, which should be read as "unknown" rather than "absent".
 */
 package sdk
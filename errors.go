@@ -1,6 +1,7 @@
 package sdk
 
 import (
+	"context"
 	"errors"
 	"fmt"
 )
@@ -12,15 +13,31 @@ var (
 	// ErrHostResponseInvalid signals that the host returned an invalid or unexpected payload.
 	ErrHostResponseInvalid = errors.New("host response is invalid or unexpected")
 
+	// ErrNilHostCall indicates a host call was attempted without a host call
+	// function, such as from a zero-value client not built with New.
+	ErrNilHostCall = errors.New("host call function is nil")
+
 	// ErrHostError means the host completed the call but reported a failure status.
 	ErrHostError = errors.New("host returned an error status")
+
+	// ErrMarshalRequest indicates a capability client failed to encode a request
+	// payload. It is shared by every client so encoding failures can be detected
+	// uniformly with errors.Is.
+	ErrMarshalRequest = errors.New("failed to marshal request")
+
+	// ErrHandlerPanic indicates that a registered handler panicked and the panic
+	// was recovered.
+	ErrHandlerPanic = errors.New("handler panicked")
 )
 
 // HostStatusError indicates the host returned an error status and includes any
-// underlying host-call or status cause details.
+// underlying host-call or status cause details. Use errors.As to inspect the
+// status Code, such as telling a 400 from a 500.
 type HostStatusError struct {
 	Capability  string
 	Operation   string
+	Code        int32
+	Message     string
 	Cause       error
 	HostCallErr error
 }
@@ -37,11 +54,19 @@ func (e *HostStatusError) Error() string {
 		target = e.Operation
 	}
 
-	if e.Cause != nil {
-		return fmt.Sprintf("%s: %s: %v", target, ErrHostError, e.Cause)
+	msg := fmt.Sprintf("%s: %s", target, ErrHostError)
+	if e.Code != 0 {
+		msg = fmt.Sprintf("%s (status %d)", msg, e.Code)
 	}
 
-	return fmt.Sprintf("%s: %s", target, ErrHostError)
+	switch {
+	case e.Cause != nil:
+		return fmt.Sprintf("%s: %v", msg, e.Cause)
+	case e.Message != "":
+		return fmt.Sprintf("%s: %s", msg, e.Message)
+	default:
+		return msg
+	}
 }
 
 // Unwrap exposes sentinel and underlying causes to errors.Is/As.
@@ -55,3 +80,49 @@ func (e *HostStatusError) Unwrap() []error {
 	}
 	return errs
 }
+
+// PanicError reports a panic recovered from a registered handler, including the
+// recovered value and the stack at the point of the panic.
+type PanicError struct {
+	Value any
+	Stack []byte
+}
+
+// Error returns a human-readable panic message.
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("%s: %v", ErrHandlerPanic, e.Value)
+}
+
+// Unwrap exposes ErrHandlerPanic and, when the panic value is an error, that
+// error to errors.Is/As.
+func (e *PanicError) Unwrap() []error {
+	errs := []error{ErrHandlerPanic}
+	if err, ok := e.Value.(error); ok {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+// MarshalRequest encodes a request message for a host call and wraps any
+// failure with ErrMarshalRequest.
+func MarshalRequest(msg interface{ MarshalVT() ([]byte, error) }) ([]byte, error) {
+	b, err := msg.MarshalVT()
+	if err != nil {
+		return nil, errors.Join(ErrMarshalRequest, err)
+	}
+
+	return b, nil
+}
+
+// HostCallError classifies err from a host call that returned no payload.
+// Cancellation and deadline errors are returned unchanged, since the caller
+// abandoned the call rather than the host failing it, so they never match
+// ErrHostCall. Any other error is joined with ErrHostCall. Every capability
+// client uses it, so errors.Is gives the same answer whichever client failed.
+func HostCallError(err error) error {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return err
+	}
+
+	return errors.Join(ErrHostCall, err)
+}
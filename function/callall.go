@@ -0,0 +1,72 @@
+package function
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Invocation is a single function call made by CallAll.
+type Invocation struct {
+	// Name is the function route to invoke.
+	Name string
+
+	// Input is the payload passed to the function.
+	Input []byte
+}
+
+// Result is the outcome of one Invocation, at the same index as its call.
+type Result struct {
+	// Output is the function output when Err is nil.
+	Output []byte
+
+	// Err is the error returned for the call, as from Call.
+	Err error
+}
+
+// CallAll invokes every call and returns their results in the order of calls.
+// Up to Config.MaxConcurrency calls run at once; with the default of one they
+// run sequentially on the calling goroutine.
+//
+// Every call is attempted and reported in its Result, so one failure does not
+// stop the others. The returned error joins each failure, annotated with its
+// index and name, and is nil when every call succeeds. Once ctx is done, calls
+// that have not started fail with an error wrapping the context error.
+func (c *HostFunction) CallAll(ctx context.Context, calls []Invocation) ([]Result, error) {
+	results := make([]Result, len(calls))
+
+	invoke := func(i int) {
+		results[i].Output, results[i].Err = c.callContext(ctx, calls[i].Name, calls[i].Input)
+	}
+
+	if c.maxConcurrency <= 1 {
+		for i := range calls {
+			invoke(i)
+		}
+	} else {
+		var wg sync.WaitGroup
+		sem := make(chan struct{}, c.maxConcurrency)
+		for i := range calls {
+			wg.Add(1)
+			sem <- struct{}{}
+			go func() {
+				defer func() {
+					<-sem
+					wg.Done()
+				}()
+				invoke(i)
+			}()
+		}
+		wg.Wait()
+	}
+
+	var errs []error
+	for i, result := range results {
+		if result.Err != nil {
+			errs = append(errs, fmt.Errorf("call %d (%s): %w", i, calls[i].Name, result.Err))
+		}
+	}
+
+	return results, errors.Join(errs...)
+}
@@ -0,0 +1,152 @@
+package function
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	sdk "github.com/tarmac-project/sdk"
+	"github.com/tarmac-project/sdk/hostmock"
+)
+
+func TestCallAll(t *testing.T) {
+	t.Parallel()
+
+	errDownstream := errors.New("downstream failed")
+
+	// echo answers with the function name and input, failing calls to "broken".
+	// It records the peak number of calls in flight at once.
+	newEcho := func(inFlight, peak *atomic.Int32) HostCall {
+		return func(_, _, function string, payload []byte) ([]byte, error) {
+			n := inFlight.Add(1)
+			defer inFlight.Add(-1)
+			for {
+				p := peak.Load()
+				if n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+
+			if function == "broken" {
+				return nil, errDownstream
+			}
+			return append([]byte(function+":"), payload...), nil
+		}
+	}
+
+	calls := []Invocation{
+		{Name: "alpha", Input: []byte("1")},
+		{Name: "broken", Input: []byte("2")},
+		{Name: "gamma", Input: []byte("3")},
+		{Name: "delta", Input: []byte("4")},
+		{Name: "epsilon", Input: []byte("5")},
+	}
+
+	tt := []struct {
+		name        string
+		concurrency int
+		wantPeak    int32
+	}{
+		{name: "default runs sequentially", concurrency: 0, wantPeak: 1},
+		{name: "concurrency of one", concurrency: 1, wantPeak: 1},
+		{name: "bounded concurrency", concurrency: 2, wantPeak: 2},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var inFlight, peak atomic.Int32
+			client, err := New(Config{HostCall: newEcho(&inFlight, &peak), MaxConcurrency: tc.concurrency})
+			if err != nil {
+				t.Fatalf("New returned error: %v", err)
+			}
+
+			results, err := client.CallAll(context.Background(), calls)
+			if !errors.Is(err, errDownstream) || !errors.Is(err, sdk.ErrHostCall) {
+				t.Fatalf("expected joined error to wrap the failed call, got %v", err)
+			}
+
+			if len(results) != len(calls) {
+				t.Fatalf("expected %d results, got %d", len(calls), len(results))
+			}
+			for i, call := range calls {
+				if call.Name == "broken" {
+					if !errors.Is(results[i].Err, errDownstream) || results[i].Output != nil {
+						t.Fatalf("result %d: expected failure, got %q, %v", i, results[i].Output, results[i].Err)
+					}
+					continue
+				}
+				want := call.Name + ":" + string(call.Input)
+				if results[i].Err != nil || string(results[i].Output) != want {
+					t.Fatalf("result %d: want %q, got %q, %v", i, want, results[i].Output, results[i].Err)
+				}
+			}
+
+			if got := peak.Load(); got > tc.wantPeak {
+				t.Fatalf("expected at most %d calls in flight, got %d", tc.wantPeak, got)
+			}
+		})
+	}
+
+	t.Run("all succeed", func(t *testing.T) {
+		t.Parallel()
+
+		mock, err := hostmock.New(hostmock.Config{Response: func() []byte { return []byte("ok") }})
+		if err != nil {
+			t.Fatalf("failed to create hostmock: %v", err)
+		}
+
+		client, err := New(Config{HostCall: mock.HostCall, MaxConcurrency: 3})
+		if err != nil {
+			t.Fatalf("New returned error: %v", err)
+		}
+
+		results, err := client.CallAll(context.Background(), calls[:3])
+		if err != nil {
+			t.Fatalf("CallAll returned error: %v", err)
+		}
+		if len(results) != 3 || mock.Count() != 3 {
+			t.Fatalf("expected 3 results and 3 host calls, got %d and %d", len(results), mock.Count())
+		}
+	})
+
+	t.Run("canceled context skips the host", func(t *testing.T) {
+		t.Parallel()
+
+		client, err := New(Config{HostCall: hostmock.Unexpected(t)})
+		if err != nil {
+			t.Fatalf("New returned error: %v", err)
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		results, err := client.CallAll(ctx, calls[:2])
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("expected %v, got %v", context.Canceled, err)
+		}
+		for i, result := range results {
+			if !errors.Is(result.Err, context.Canceled) {
+				t.Fatalf("result %d: expected %v, got %v", i, context.Canceled, result.Err)
+			}
+		}
+	})
+
+	t.Run("no calls", func(t *testing.T) {
+		t.Parallel()
+
+		client, err := New(Config{HostCall: hostmock.Unexpected(t)})
+		if err != nil {
+			t.Fatalf("New returned error: %v", err)
+		}
+
+		results, err := client.CallAll(context.Background(), nil)
+		if err != nil || len(results) != 0 {
+			t.Fatalf("expected no results and no error, got %v, %v", results, err)
+		}
+	})
+}
@@ -3,6 +3,17 @@ Package function provides a client for invoking function-to-function callbacks
 through the Tarmac host runtime.
 
 The package exposes a minimal raw-bytes API: callers supply a function name and
-input payload, and receive the target function output bytes.
+input payload, and receive the target function output bytes. CallWithFallback
+supports graceful degradation by returning a caller-supplied fallback when the
+downstream call fails. CallRPC layers a JSON-RPC 2.0 envelope over Call: it
+sends an RPCRequest, decodes the RPCResponse result, and returns errors
+reported by the called function as an *RPCError matching ErrRPC, distinct from
+transport failures. Config.Timeout, defaulting to the SDK DefaultTimeout,
+bounds each call.
+
+CallAll fans out a batch of Invocations and returns one Result per call, in
+order, with every failure joined into the returned error. Calls run
+sequentially unless Config.MaxConcurrency allows more to run at once, and a
+done context fails the calls that have not started.
 */
 package function
@@ -1,13 +1,17 @@
 package function
 
 import (
+	"context"
 	"errors"
 	"strings"
+	"sync/atomic"
+	"time"
 
 	sdk "github.com/tarmac-project/sdk"
 	wapc "github.com/wapc/wapc-guest-tinygo"
 )
 
+// capabilityName is the default host capability name for function calls.
 const capabilityName = "function"
 
 // HostCall defines the waPC host function signature used by function calls.
@@ -17,6 +21,21 @@ type HostCall func(string, string, string, []byte) ([]byte, error)
 type Client interface {
 	// Call invokes a function route by name and returns its raw output bytes.
 	Call(name string, input []byte) ([]byte, error)
+
+	// CallWithFallback invokes a function route and returns fallback when the
+	// host call fails, while still reporting invalid function names.
+	CallWithFallback(name string, input []byte, fallback []byte) ([]byte, error)
+
+	// CallAll invokes each call, up to Config.MaxConcurrency at a time, and
+	// returns their results in order along with the joined per-call errors.
+	CallAll(ctx context.Context, calls []Invocation) ([]Result, error)
+
+	// CallRPC invokes a function route with a JSON-RPC 2.0 request for method
+	// and params and decodes the response result into result.
+	CallRPC(name, method string, params, result any) error
+
+	// Close releases resources held by the client.
+	Close() error
 }
 
 // Config controls how a Client instance interacts with the host runtime.
@@ -26,12 +45,34 @@ type Config struct {
 
 	// HostCall overrides the waPC host function used for function invocations.
 	HostCall HostCall
+
+	// Timeout bounds each host call. When zero, SDKConfig.DefaultTimeout is
+	// used; a negative value disables the timeout even when a default is set.
+	Timeout time.Duration
+
+	// Capability overrides the host capability name used for host calls, for
+	// hosts that register the capability under a custom name. When empty,
+	// "function" is used.
+	Capability string
+
+	// MaxConcurrency bounds how many CallAll invocations run at once. When
+	// zero or one, calls run one at a time on the calling goroutine, which is
+	// the safe choice for hosts whose waPC calls are not goroutine-safe.
+	MaxConcurrency int
 }
 
 // HostFunction is the functions capability client implementation.
 type HostFunction struct {
-	runtime  sdk.RuntimeConfig
-	hostCall HostCall
+	runtime    sdk.RuntimeConfig
+	hostCall   HostCall
+	timeout    time.Duration
+	capability string
+
+	// maxConcurrency is the CallAll concurrency limit, at least one.
+	maxConcurrency int
+
+	// rpcID numbers CallRPC requests so responses can be matched to them.
+	rpcID atomic.Uint64
 }
 
 // Ensure HostFunction satisfies the Client interface at compile time.
@@ -54,19 +95,65 @@ func New(config Config) (*HostFunction, error) {
 		hostCall = wapc.HostCall
 	}
 
-	return &HostFunction{runtime: runtime, hostCall: hostCall}, nil
+	// Per-client timeouts take precedence over the runtime default.
+	timeout := config.Timeout
+	if timeout == 0 {
+		timeout = runtime.DefaultTimeout
+	}
+
+	capability := config.Capability
+	if capability == "" {
+		capability = capabilityName
+	}
+
+	return &HostFunction{
+		runtime:        runtime,
+		hostCall:       hostCall,
+		timeout:        timeout,
+		capability:     capability,
+		maxConcurrency: max(config.MaxConcurrency, 1),
+	}, nil
 }
 
-// Call invokes a function route by name and returns its raw output bytes.
+// Call invokes a function route by name and returns its raw output bytes. An
+// empty output is a successful result, as from a function that returns nothing.
 func (c *HostFunction) Call(name string, input []byte) ([]byte, error) {
+	return c.callContext(c.runtime.Context(), name, input)
+}
+
+// callContext is Call bounded by ctx as well as the configured timeout.
+func (c *HostFunction) callContext(ctx context.Context, name string, input []byte) ([]byte, error) {
 	if strings.TrimSpace(name) == "" {
 		return nil, ErrInvalidFunctionName
 	}
 
-	resp, err := c.hostCall(c.runtime.Namespace, capabilityName, name, input)
+	resp, err := c.call(ctx, name, input)
 	if err != nil {
-		return nil, errors.Join(sdk.ErrHostCall, err)
+		return nil, sdk.HostCallError(err)
 	}
 
 	return resp, nil
 }
+
+// CallWithFallback invokes a function route by name and returns fallback with a
+// nil error when the host call fails or times out. Validation errors such as
+// ErrInvalidFunctionName and cancellation of the request context are still
+// returned.
+func (c *HostFunction) CallWithFallback(name string, input []byte, fallback []byte) ([]byte, error) {
+	resp, err := c.Call(name, input)
+	if errors.Is(err, sdk.ErrHostCall) || errors.Is(err, context.DeadlineExceeded) {
+		return fallback, nil
+	}
+
+	return resp, err
+}
+
+// Close releases resources associated with the client. It is a no-op.
+func (c *HostFunction) Close() error {
+	return nil
+}
+
+// call issues a function host call bounded by ctx and the configured timeout.
+func (c *HostFunction) call(ctx context.Context, name string, input []byte) ([]byte, error) {
+	return sdk.WithRequestContext(ctx, c.runtime).Call(c.hostCall, c.timeout, c.capability, name, input)
+}
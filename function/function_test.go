@@ -2,9 +2,11 @@ package function
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"reflect"
 	"testing"
+	"time"
 
 	sdk "github.com/tarmac-project/sdk"
 	"github.com/tarmac-project/sdk/hostmock"
@@ -181,3 +183,229 @@ func TestCall(t *testing.T) {
 		})
 	}
 }
+
+func TestCallWithFallback(t *testing.T) {
+	t.Parallel()
+
+	release := make(chan struct{})
+	t.Cleanup(func() { close(release) })
+
+	fallback := []byte("fallback")
+
+	tt := []struct {
+		name       string
+		fn         string
+		timeout    time.Duration
+		hostCall   HostCall
+		wantOutput []byte
+		wantErr    error
+	}{
+		{
+			name: "success returns output",
+			fn:   "target-func",
+			hostCall: func(string, string, string, []byte) ([]byte, error) {
+				return []byte("result"), nil
+			},
+			wantOutput: []byte("result"),
+		},
+		{
+			name: "host error returns fallback",
+			fn:   "target-func",
+			hostCall: func(string, string, string, []byte) ([]byte, error) {
+				return nil, errors.New("boom")
+			},
+			wantOutput: fallback,
+		},
+		{
+			name:    "timeout returns fallback",
+			fn:      "target-func",
+			timeout: 10 * time.Millisecond,
+			hostCall: func(string, string, string, []byte) ([]byte, error) {
+				<-release
+				return []byte("late"), nil
+			},
+			wantOutput: fallback,
+		},
+		{
+			name: "invalid name is not masked",
+			fn:   " ",
+			hostCall: func(string, string, string, []byte) ([]byte, error) {
+				return nil, errors.New("unexpected host call")
+			},
+			wantErr: ErrInvalidFunctionName,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			c, err := New(Config{HostCall: tc.hostCall, Timeout: tc.timeout})
+			if err != nil {
+				t.Fatalf("New returned error: %v", err)
+			}
+
+			got, gotErr := c.CallWithFallback(tc.fn, []byte("payload"), fallback)
+			if !errors.Is(gotErr, tc.wantErr) {
+				t.Fatalf("unexpected error: want %v got %v", tc.wantErr, gotErr)
+			}
+
+			if !bytes.Equal(got, tc.wantOutput) {
+				t.Fatalf("output mismatch: want %q got %q", string(tc.wantOutput), string(got))
+			}
+		})
+	}
+}
+
+func TestContextErrors(t *testing.T) {
+	t.Parallel()
+
+	release := make(chan struct{})
+	t.Cleanup(func() { close(release) })
+
+	blocking := func(string, string, string, []byte) ([]byte, error) {
+		<-release
+		return []byte("late"), nil
+	}
+
+	t.Run("deadline is not a host call failure", func(t *testing.T) {
+		t.Parallel()
+
+		c, err := New(Config{HostCall: blocking, Timeout: 10 * time.Millisecond})
+		if err != nil {
+			t.Fatalf("New returned error: %v", err)
+		}
+
+		_, err = c.Call("target-func", nil)
+		if !errors.Is(err, context.DeadlineExceeded) || errors.Is(err, sdk.ErrHostCall) {
+			t.Fatalf("expected %v without %v, got %v", context.DeadlineExceeded, sdk.ErrHostCall, err)
+		}
+	})
+
+	t.Run("cancelled request is not masked by fallback", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		c, err := New(Config{SDKConfig: sdk.WithRequestContext(ctx, sdk.RuntimeConfig{}), HostCall: blocking})
+		if err != nil {
+			t.Fatalf("New returned error: %v", err)
+		}
+
+		got, err := c.CallWithFallback("target-func", nil, []byte("fallback"))
+		if !errors.Is(err, context.Canceled) || errors.Is(err, sdk.ErrHostCall) {
+			t.Fatalf("expected %v without %v, got %v", context.Canceled, sdk.ErrHostCall, err)
+		}
+		if got != nil {
+			t.Fatalf("expected no output, got %q", got)
+		}
+	})
+}
+
+func TestCapabilityOverride(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		name       string
+		capability string
+		want       string
+	}{
+		{name: "default", want: capabilityName},
+		{name: "custom", capability: "custom-function", want: "custom-function"},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			mock, err := hostmock.New(hostmock.Config{
+				ExpectedCapability: tc.want,
+				Response:           func() []byte { return []byte("ok") },
+			})
+			if err != nil {
+				t.Fatalf("failed to create hostmock: %v", err)
+			}
+
+			client, err := New(Config{HostCall: mock.HostCall, Capability: tc.capability})
+			if err != nil {
+				t.Fatalf("New returned error: %v", err)
+			}
+			if _, err := client.Call("target-func", nil); err != nil {
+				t.Fatalf("Call returned error: %v", err)
+			}
+
+			calls := mock.Calls()
+			if len(calls) != 1 || calls[0].Capability != tc.want {
+				t.Fatalf("expected one call to capability %q, got %+v", tc.want, calls)
+			}
+		})
+	}
+}
+
+func TestClose(t *testing.T) {
+	t.Parallel()
+
+	mock, err := hostmock.New(hostmock.Config{})
+	if err != nil {
+		t.Fatalf("failed to create hostmock: %v", err)
+	}
+
+	var client Client
+	client, err = New(Config{HostCall: mock.HostCall})
+	if err != nil {
+		t.Fatalf("New returned error: %v", err)
+	}
+
+	// Close must be safe to defer unconditionally and to call more than once.
+	for i := range 2 {
+		if err := client.Close(); err != nil {
+			t.Fatalf("Close call %d returned error: %v", i+1, err)
+		}
+	}
+	if mock.Count() != 0 {
+		t.Fatalf("expected Close to make no host calls, got %d", mock.Count())
+	}
+}
+
+func TestZeroValueClient(t *testing.T) {
+	t.Parallel()
+
+	// A client not built with New has no host call; it must fail rather than panic.
+	var client HostFunction
+	if _, err := client.Call("other", nil); !errors.Is(err, sdk.ErrNilHostCall) || !errors.Is(err, sdk.ErrHostCall) {
+		t.Fatalf("expected ErrNilHostCall wrapped in ErrHostCall, got %v", err)
+	}
+}
+
+func TestEmptyHostResponse(t *testing.T) {
+	t.Parallel()
+
+	for kind, payload := range map[string][]byte{"nil": nil, "empty": {}} {
+		t.Run(kind, func(t *testing.T) {
+			t.Parallel()
+
+			mock, err := hostmock.New(hostmock.Config{Response: func() []byte { return payload }})
+			if err != nil {
+				t.Fatalf("failed to create hostmock: %v", err)
+			}
+
+			client, err := New(Config{HostCall: mock.HostCall})
+			if err != nil {
+				t.Fatalf("New returned error: %v", err)
+			}
+
+			// A function may legitimately return nothing, so Call succeeds.
+			output, err := client.Call("other", []byte("input"))
+			if err != nil || len(output) != 0 {
+				t.Fatalf("expected empty output and no error, got %q, %v", output, err)
+			}
+
+			// A JSON-RPC call must always be answered with an envelope.
+			err = client.CallRPC("other", "add", nil, nil)
+			if !errors.Is(err, sdk.ErrHostResponseInvalid) || !errors.Is(err, ErrDecodeRPC) {
+				t.Fatalf("expected %v and %v, got %v", sdk.ErrHostResponseInvalid, ErrDecodeRPC, err)
+			}
+		})
+	}
+}
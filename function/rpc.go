@@ -0,0 +1,142 @@
+package function
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	sdk "github.com/tarmac-project/sdk"
+)
+
+// RPCVersion is the JSON-RPC protocol version carried in every envelope.
+const RPCVersion = "2.0"
+
+var (
+	// ErrInvalidMethod indicates an empty or whitespace-only RPC method name.
+	ErrInvalidMethod = errors.New("rpc method is invalid")
+
+	// ErrEncodeRPC wraps failures while encoding RPC params.
+	ErrEncodeRPC = errors.New("failed to encode rpc request")
+
+	// ErrDecodeRPC wraps failures while decoding an RPC response envelope or
+	// its result, including envelopes that do not answer the request.
+	ErrDecodeRPC = errors.New("failed to decode rpc response")
+
+	// ErrRPC is matched by every *RPCError, so callers can tell an error
+	// reported by the called function from a transport failure.
+	ErrRPC = errors.New("rpc call returned an error")
+)
+
+// RPCRequest is the JSON-RPC 2.0 envelope CallRPC sends as the function input.
+// Functions serving RPC calls decode their input into it.
+type RPCRequest struct {
+	// JSONRPC is always RPCVersion.
+	JSONRPC string `json:"jsonrpc"`
+
+	// Method names the procedure to run.
+	Method string `json:"method"`
+
+	// Params holds the JSON-encoded params, omitted when there are none.
+	Params json.RawMessage `json:"params,omitempty"`
+
+	// ID identifies the request and must be echoed in the response.
+	ID uint64 `json:"id"`
+}
+
+// RPCResponse is the JSON-RPC 2.0 envelope CallRPC expects as the function
+// output. Exactly one of Result and Error is set.
+type RPCResponse struct {
+	// JSONRPC is always RPCVersion.
+	JSONRPC string `json:"jsonrpc"`
+
+	// Result holds the JSON-encoded result of a successful call.
+	Result json.RawMessage `json:"result,omitempty"`
+
+	// Error describes a failed call.
+	Error *RPCError `json:"error,omitempty"`
+
+	// ID is the ID of the request being answered.
+	ID uint64 `json:"id"`
+}
+
+// RPCError is an error reported by the called function in an RPCResponse.
+type RPCError struct {
+	// Code is the JSON-RPC error code, such as -32601 for an unknown method.
+	Code int `json:"code"`
+
+	// Message is a short description of the error.
+	Message string `json:"message"`
+
+	// Data holds optional JSON-encoded error details.
+	Data json.RawMessage `json:"data,omitempty"`
+}
+
+// Error returns the code and message of the RPC error.
+func (e *RPCError) Error() string {
+	return fmt.Sprintf("rpc error %d: %s", e.Code, e.Message)
+}
+
+// Is reports whether target is ErrRPC.
+func (e *RPCError) Is(target error) bool {
+	return target == ErrRPC
+}
+
+// CallRPC invokes the function route name with a JSON-RPC 2.0 request for
+// method and params, and decodes the response result into result. A nil params
+// omits params from the request and a nil result discards the result.
+//
+// Errors reported by the called function are returned as an *RPCError matching
+// ErrRPC. Transport failures are returned as from Call, wrapping
+// sdk.ErrHostCall, and malformed or mismatched responses wrap ErrDecodeRPC. An
+// empty response also wraps sdk.ErrHostResponseInvalid.
+func (c *HostFunction) CallRPC(name, method string, params, result any) error {
+	if strings.TrimSpace(method) == "" {
+		return ErrInvalidMethod
+	}
+
+	req := RPCRequest{JSONRPC: RPCVersion, Method: method, ID: c.rpcID.Add(1)}
+	if params != nil {
+		raw, err := json.Marshal(params)
+		if err != nil {
+			return errors.Join(ErrEncodeRPC, err)
+		}
+		req.Params = raw
+	}
+
+	input, err := json.Marshal(req)
+	if err != nil {
+		return errors.Join(ErrEncodeRPC, err)
+	}
+
+	output, err := c.Call(name, input)
+	if err != nil {
+		return err
+	}
+
+	if len(output) == 0 {
+		return fmt.Errorf("%w: %w: empty response", ErrDecodeRPC, sdk.ErrHostResponseInvalid)
+	}
+
+	var resp RPCResponse
+	if err := json.Unmarshal(output, &resp); err != nil {
+		return errors.Join(ErrDecodeRPC, err)
+	}
+
+	if resp.JSONRPC != RPCVersion || resp.ID != req.ID {
+		return fmt.Errorf("%w: response version %q id %d does not answer request id %d",
+			ErrDecodeRPC, resp.JSONRPC, resp.ID, req.ID)
+	}
+
+	if resp.Error != nil {
+		return resp.Error
+	}
+
+	if result != nil && len(resp.Result) > 0 {
+		if err := json.Unmarshal(resp.Result, result); err != nil {
+			return errors.Join(ErrDecodeRPC, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,179 @@
+package function
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	sdk "github.com/tarmac-project/sdk"
+	"github.com/tarmac-project/sdk/hostmock"
+)
+
+type addParams struct {
+	A int `json:"a"`
+	B int `json:"b"`
+}
+
+// rpcServer answers CallRPC requests the way a JSON-RPC function would, letting
+// handle shape the response for each test case.
+func rpcServer(handle func(req RPCRequest) RPCResponse) HostCall {
+	return func(_, _, _ string, payload []byte) ([]byte, error) {
+		var req RPCRequest
+		if err := json.Unmarshal(payload, &req); err != nil {
+			return nil, err
+		}
+		resp := handle(req)
+		return json.Marshal(resp)
+	}
+}
+
+func TestCallRPC(t *testing.T) {
+	t.Parallel()
+
+	failure := func(id uint64, code int, message string) RPCResponse {
+		return RPCResponse{JSONRPC: RPCVersion, ID: id, Error: &RPCError{Code: code, Message: message}}
+	}
+
+	add := rpcServer(func(req RPCRequest) RPCResponse {
+		if req.JSONRPC != RPCVersion || req.Method != "add" {
+			return failure(req.ID, -32601, "method not found")
+		}
+		var p addParams
+		if err := json.Unmarshal(req.Params, &p); err != nil {
+			return failure(req.ID, -32602, "invalid params")
+		}
+		result, _ := json.Marshal(p.A + p.B)
+		return RPCResponse{JSONRPC: RPCVersion, ID: req.ID, Result: result}
+	})
+
+	tt := []struct {
+		name     string
+		hostCall HostCall
+		method   string
+		params   any
+		want     int
+		wantErr  error
+		wantCode int
+	}{
+		{name: "result decoded", hostCall: add, method: "add", params: addParams{A: 1, B: 2}, want: 3},
+		{
+			name:     "rpc error",
+			hostCall: add,
+			method:   "subtract",
+			params:   addParams{},
+			wantErr:  ErrRPC,
+			wantCode: -32601,
+		},
+		{
+			name:     "params omitted when nil",
+			hostCall: add,
+			method:   "add",
+			wantErr:  ErrRPC,
+			wantCode: -32602,
+		},
+		{
+			name: "transport failure",
+			hostCall: func(string, string, string, []byte) ([]byte, error) {
+				return nil, errors.New("host unavailable")
+			},
+			method:  "add",
+			wantErr: sdk.ErrHostCall,
+		},
+		{
+			name: "malformed response",
+			hostCall: func(string, string, string, []byte) ([]byte, error) {
+				return []byte("not json"), nil
+			},
+			method:  "add",
+			wantErr: ErrDecodeRPC,
+		},
+		{
+			name: "mismatched id",
+			hostCall: rpcServer(func(req RPCRequest) RPCResponse {
+				return RPCResponse{JSONRPC: RPCVersion, ID: req.ID + 1, Result: json.RawMessage("3")}
+			}),
+			method:  "add",
+			wantErr: ErrDecodeRPC,
+		},
+		{
+			name: "result type mismatch",
+			hostCall: rpcServer(func(req RPCRequest) RPCResponse {
+				return RPCResponse{JSONRPC: RPCVersion, ID: req.ID, Result: json.RawMessage(`"three"`)}
+			}),
+			method:  "add",
+			wantErr: ErrDecodeRPC,
+		},
+		{name: "invalid method", method: " ", wantErr: ErrInvalidMethod},
+		{name: "unencodable params", method: "add", params: make(chan int), wantErr: ErrEncodeRPC},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			hostCall := tc.hostCall
+			if hostCall == nil {
+				hostCall = hostmock.Unexpected(t)
+			}
+
+			client, err := New(Config{HostCall: hostCall})
+			if err != nil {
+				t.Fatalf("New returned error: %v", err)
+			}
+
+			var got int
+			err = client.CallRPC("calc", tc.method, tc.params, &got)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("expected error %v, got %v", tc.wantErr, err)
+			}
+			if tc.wantErr != ErrRPC && errors.Is(err, ErrRPC) {
+				t.Fatalf("transport error reported as an rpc error: %v", err)
+			}
+			if got != tc.want {
+				t.Fatalf("result mismatch: want %d, got %d", tc.want, got)
+			}
+
+			if tc.wantCode != 0 {
+				var rpcErr *RPCError
+				if !errors.As(err, &rpcErr) || rpcErr.Code != tc.wantCode {
+					t.Fatalf("expected *RPCError with code %d, got %v", tc.wantCode, err)
+				}
+			}
+		})
+	}
+}
+
+func TestCallRPCHostMock(t *testing.T) {
+	t.Parallel()
+
+	mock, err := hostmock.New(hostmock.Config{
+		ExpectedNamespace:  sdk.DefaultNamespace,
+		ExpectedCapability: "function",
+		ExpectedFunction:   "calc",
+		PayloadValidator: func(payload []byte) error {
+			want := `{"jsonrpc":"2.0","method":"add","params":{"a":1,"b":2},"id":1}`
+			if string(payload) != want {
+				return fmt.Errorf("request mismatch: want %s, got %s", want, payload)
+			}
+			return nil
+		},
+		Response: func() []byte { return []byte(`{"jsonrpc":"2.0","result":3,"id":1}`) },
+	})
+	if err != nil {
+		t.Fatalf("failed to create hostmock: %v", err)
+	}
+
+	client, err := New(Config{HostCall: mock.HostCall})
+	if err != nil {
+		t.Fatalf("New returned error: %v", err)
+	}
+
+	var got int
+	if err := client.CallRPC("calc", "add", addParams{A: 1, B: 2}, &got); err != nil {
+		t.Fatalf("CallRPC returned error: %v", err)
+	}
+	if got != 3 {
+		t.Fatalf("result mismatch: want 3, got %d", got)
+	}
+}
@@ -2,4 +2,9 @@ module github.com/tarmac-project/sdk
 
 go 1.23
 
-require github.com/wapc/wapc-guest-tinygo v0.3.3
+require (
+	github.com/tarmac-project/protobuf-go v0.1.0
+	github.com/wapc/wapc-guest-tinygo v0.3.3
+)
+
+require github.com/aperturerobotics/protobuf-go-lite v0.11.0 // indirect
@@ -9,7 +9,14 @@ Why use hostmock?
 
   - Validate routing: ensure calls use the expected namespace, capability, and function when you set them.
   - Inspect payloads: plug in a PayloadValidator to assert protobuf contents.
-  - Script responses: return custom bytes or simulate failures.
+  - Script responses: return custom bytes, a sequence of bytes across calls, or simulate failures.
+  - Count calls: set ExpectedCalls and call AssertExpectations to catch skipped or repeated calls.
+  - Inspect traffic: Calls and Payloads return everything the mock received, in order.
+  - Reuse mocks: Reset clears recorded calls and restarts Responses between subtests.
+  - Route functions: register per-function behaviour in Functions to serve a whole capability from one mock.
+  - Simulate latency: set Delay or Block to exercise client timeouts.
+  - Forbid calls: pass Unexpected(t) where a code path must never reach the host.
+  - Limit payloads: set MaxPayloadBytes to reject oversized requests when fuzzing a client.
 
 When should I use it?
 
@@ -35,11 +42,34 @@ Quick start
 
 Behavior
 
+  - New returns ErrInvalidConfig for settings that conflict or would be
+    ignored: both Response and Responses, negative ExpectedCalls, Delay, or
+    MaxPayloadBytes, FailAfter with SucceedAfter or Fail, or Functions mixed
+    with ExpectedFunction or top-level response settings.
   - If Fail is true and Error is set, HostCall returns that error.
   - If Fail is true and Error is nil, HostCall returns ErrOperationFailed.
+  - FailAfter lets the first N calls succeed and fails the rest, while
+    SucceedAfter fails the first N calls and lets the rest succeed, for
+    testing intermittent hosts. Calls are numbered as Count reports them, so
+    Reset starts the count over.
   - Otherwise, HostCall enforces ExpectedNamespace/Capability/Function and runs
     PayloadValidator when provided. If everything is in order, Response (when set)
     provides the return bytes; otherwise it returns nil.
+  - When MaxPayloadBytes is set, larger payloads fail with ErrPayloadTooLarge
+    after routing is checked and before Functions or PayloadValidator run.
+  - When Responses is set, each call returns the next entry instead of Response,
+    repeating the last entry once the sequence runs out. An entry that returns
+    an error fails that call, so a flaky host can fail and then succeed, or a
+    lookup can miss and then hit.
+  - Every HostCall is counted, including ones rejected by validation. Count
+    reports the total, and AssertExpectations fails the test when ExpectedCalls
+    is non-zero and does not match. Calls and Payloads return copies of the
+    recorded routing and payloads for inspection after the fact.
+  - When Functions is set, namespace and capability are still checked, then the
+    call is handed to the FunctionConfig registered for its function name.
+    Unregistered functions return ErrUnexpectedFunction.
+  - Each call waits for Block to close, when set, and then sleeps for Delay
+    before any validation runs.
 
 Tips
 
@@ -1,8 +1,13 @@
 package hostmock
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
+	"slices"
+	"sync"
+	"testing"
+	"time"
 )
 
 var (
@@ -17,8 +22,64 @@ var (
 
 	// ErrOperationFailed is returned when Fail is set without a custom error.
 	ErrOperationFailed = errors.New("operation failed")
+
+	// ErrUnexpectedCall is returned by the host call from Unexpected.
+	ErrUnexpectedCall = errors.New("unexpected host call")
+
+	// ErrPayloadTooLarge is returned when a payload exceeds MaxPayloadBytes.
+	ErrPayloadTooLarge = errors.New("payload too large")
+
+	// ErrInvalidConfig is returned by New when Config contains settings that
+	// contradict each other or would silently have no effect.
+	ErrInvalidConfig = errors.New("invalid hostmock config")
 )
 
+// Clock supplies the timers that drive Config.Delay. It is satisfied by
+// sdk.Clock, so tests can share one fake clock between the SDK and the mock.
+type Clock interface {
+	// After returns a channel that receives the current time once d has
+	// elapsed.
+	After(d time.Duration) <-chan time.Time
+}
+
+// systemClock implements Clock with the time package.
+type systemClock struct{}
+
+// FunctionConfig configures how a Mock responds to a single function when
+// routing by function name through Config.Functions.
+type FunctionConfig struct {
+	// Error is the error to return if the function is configured to fail.
+	Error error
+
+	// PayloadValidator validates the payload passed to the function.
+	PayloadValidator func([]byte) error
+
+	// Response defines the response to return for the function.
+	Response func() []byte
+
+	// Responses defines successive results for the function, following the
+	// same rules as Config.Responses.
+	Responses []func() ([]byte, error)
+
+	// Fail indicates whether the function should return an error.
+	Fail bool
+}
+
+// Call records the routing and payload of a single HostCall invocation.
+type Call struct {
+	// Namespace is the namespace passed to HostCall.
+	Namespace string
+
+	// Capability is the capability passed to HostCall.
+	Capability string
+
+	// Function is the function name passed to HostCall.
+	Function string
+
+	// Payload is a copy of the payload passed to HostCall.
+	Payload []byte
+}
+
 // Mock simulates a host call interface with validation and configurable responses.
 type Mock struct {
 	// ExpectedNamespace defines the namespace expected in the host call.
@@ -39,8 +100,51 @@ type Mock struct {
 	// Response defines the response to return for the host call.
 	Response func() []byte
 
+	// Responses defines successive results, one per host call. Once the
+	// sequence is exhausted the last entry is repeated. When set, it takes
+	// precedence over Response, and an entry returning an error fails that
+	// call regardless of Fail.
+	Responses []func() ([]byte, error)
+
 	// Fail indicates whether the mock should return an error.
 	Fail bool
+
+	// FailAfter, when positive, lets the first FailAfter calls succeed and
+	// fails every later call as if Fail were set.
+	FailAfter int
+
+	// SucceedAfter, when positive, fails the first SucceedAfter calls as if
+	// Fail were set and lets every later call succeed.
+	SucceedAfter int
+
+	// ExpectedCalls is the number of host calls AssertExpectations requires.
+	// Zero skips the check.
+	ExpectedCalls int
+
+	// Delay is how long each host call waits before returning.
+	Delay time.Duration
+
+	// Clock times Delay. When nil, the real clock is used.
+	Clock Clock
+
+	// Block, when non-nil, holds each host call until the channel is closed.
+	Block chan struct{}
+
+	// MaxPayloadBytes, when positive, rejects larger payloads with
+	// ErrPayloadTooLarge before PayloadValidator runs.
+	MaxPayloadBytes int
+
+	// mu guards next and calls.
+	mu sync.Mutex
+
+	// next is the index of the next entry in Responses.
+	next int
+
+	// calls records every HostCall invocation in order.
+	calls []Call
+
+	// functions routes calls to per-function mocks when Config.Functions is set.
+	functions map[string]*Mock
 }
 
 // Config represents the configuration for creating a Mock instance.
@@ -63,25 +167,248 @@ type Config struct {
 	// Response defines the response to return for the host call.
 	Response func() []byte
 
+	// Responses defines successive results, one per host call. Once the
+	// sequence is exhausted the last entry is repeated. When set, it takes
+	// precedence over Response, and an entry returning an error fails that
+	// call regardless of Fail.
+	Responses []func() ([]byte, error)
+
 	// Fail indicates whether the mock should return an error.
 	Fail bool
+
+	// FailAfter, when positive, lets the first FailAfter calls succeed and
+	// fails every later call as if Fail were set.
+	FailAfter int
+
+	// SucceedAfter, when positive, fails the first SucceedAfter calls as if
+	// Fail were set and lets every later call succeed.
+	SucceedAfter int
+
+	// ExpectedCalls is the number of host calls AssertExpectations requires.
+	// Zero skips the check.
+	ExpectedCalls int
+
+	// Delay is how long each host call waits before returning.
+	Delay time.Duration
+
+	// Clock times Delay, so tests can release delayed calls by advancing a
+	// fake clock instead of sleeping. When nil, the real clock is used.
+	Clock Clock
+
+	// Block, when non-nil, holds each host call until the channel is closed.
+	Block chan struct{}
+
+	// MaxPayloadBytes, when positive, rejects larger payloads with
+	// ErrPayloadTooLarge before PayloadValidator runs.
+	MaxPayloadBytes int
+
+	// Functions routes calls by function name to per-function behaviour, so a
+	// single Mock can serve clients that call several functions on one
+	// capability. Calls to unregistered functions return ErrUnexpectedFunction.
+	// When set, the top-level validator and response fields are ignored.
+	Functions map[string]FunctionConfig
 }
 
-// New creates a new instance of the Mock based on the provided Config.
+// New creates a new instance of the Mock based on the provided Config. It
+// returns ErrInvalidConfig when the configuration is contradictory.
 func New(config Config) (*Mock, error) {
+	if err := validate(config); err != nil {
+		return nil, err
+	}
+
+	var functions map[string]*Mock
+	if len(config.Functions) > 0 {
+		functions = make(map[string]*Mock, len(config.Functions))
+		for name, fn := range config.Functions {
+			functions[name] = &Mock{
+				ExpectedFunction: name,
+				Error:            fn.Error,
+				Fail:             fn.Fail,
+				PayloadValidator: fn.PayloadValidator,
+				Response:         fn.Response,
+				Responses:        fn.Responses,
+			}
+		}
+	}
+
 	return &Mock{
 		ExpectedNamespace:  config.ExpectedNamespace,
 		ExpectedCapability: config.ExpectedCapability,
 		ExpectedFunction:   config.ExpectedFunction,
 		Error:              config.Error,
 		Fail:               config.Fail,
+		FailAfter:          config.FailAfter,
+		SucceedAfter:       config.SucceedAfter,
 		PayloadValidator:   config.PayloadValidator,
 		Response:           config.Response,
+		Responses:          config.Responses,
+		ExpectedCalls:      config.ExpectedCalls,
+		Delay:              config.Delay,
+		Clock:              config.Clock,
+		Block:              config.Block,
+		MaxPayloadBytes:    config.MaxPayloadBytes,
+		functions:          functions,
 	}, nil
 }
 
+// Unexpected returns a host call that fails t whenever it is invoked, for code
+// paths that must not reach the host, such as input validation. It reports the
+// failure with t.Errorf rather than t.Fatalf because clients may make host
+// calls from another goroutine, and returns an error wrapping
+// ErrUnexpectedCall so the caller stops.
+func Unexpected(t testing.TB) func(namespace, capability, function string, payload []byte) ([]byte, error) {
+	return func(namespace, capability, function string, _ []byte) ([]byte, error) {
+		t.Helper()
+		t.Errorf("hostmock: unexpected host call to %s/%s/%s", namespace, capability, function)
+		return nil, fmt.Errorf("%w: %s/%s/%s", ErrUnexpectedCall, namespace, capability, function)
+	}
+}
+
+// validate rejects configurations whose settings conflict or would be ignored.
+// Fail combined with a Response is allowed, since clients may inspect payloads
+// returned alongside an error, as is an Error left in place while Fail is off.
+func validate(config Config) error {
+	if config.ExpectedCalls < 0 {
+		return fmt.Errorf("%w: ExpectedCalls must not be negative", ErrInvalidConfig)
+	}
+
+	if config.Delay < 0 {
+		return fmt.Errorf("%w: Delay must not be negative", ErrInvalidConfig)
+	}
+
+	if config.MaxPayloadBytes < 0 {
+		return fmt.Errorf("%w: MaxPayloadBytes must not be negative", ErrInvalidConfig)
+	}
+
+	if config.FailAfter < 0 || config.SucceedAfter < 0 {
+		return fmt.Errorf("%w: FailAfter and SucceedAfter must not be negative", ErrInvalidConfig)
+	}
+
+	if config.FailAfter > 0 && config.SucceedAfter > 0 {
+		return fmt.Errorf("%w: FailAfter and SucceedAfter are mutually exclusive", ErrInvalidConfig)
+	}
+
+	if config.Fail && (config.FailAfter > 0 || config.SucceedAfter > 0) {
+		return fmt.Errorf("%w: Fail cannot be combined with FailAfter or SucceedAfter", ErrInvalidConfig)
+	}
+
+	if len(config.Functions) == 0 {
+		return validateResponses("", config.Response, config.Responses)
+	}
+
+	if config.ExpectedFunction != "" {
+		return fmt.Errorf("%w: ExpectedFunction cannot be combined with Functions", ErrInvalidConfig)
+	}
+
+	if config.Error != nil || config.Fail || config.FailAfter > 0 || config.SucceedAfter > 0 ||
+		config.PayloadValidator != nil ||
+		config.Response != nil || len(config.Responses) > 0 {
+		return fmt.Errorf("%w: top-level behaviour is ignored when Functions is set", ErrInvalidConfig)
+	}
+
+	for name, fn := range config.Functions {
+		if name == "" {
+			return fmt.Errorf("%w: Functions contains an empty function name", ErrInvalidConfig)
+		}
+		if err := validateResponses(name, fn.Response, fn.Responses); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateResponses rejects setting both Response and Responses, since only
+// one of them would ever be used.
+func validateResponses(name string, response func() []byte, responses []func() ([]byte, error)) error {
+	if response == nil || len(responses) == 0 {
+		return nil
+	}
+
+	if name != "" {
+		return fmt.Errorf("%w: function %s: Response and Responses are mutually exclusive", ErrInvalidConfig, name)
+	}
+	return fmt.Errorf("%w: Response and Responses are mutually exclusive", ErrInvalidConfig)
+}
+
+// Count returns the number of times HostCall has been invoked, including calls
+// rejected by validation.
+func (m *Mock) Count() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.calls)
+}
+
+// Reset clears recorded calls and restarts Responses from the first entry so
+// a mock can be reused across subtests. Configuration is left unchanged.
+func (m *Mock) Reset() {
+	m.mu.Lock()
+	m.calls = nil
+	m.next = 0
+	m.mu.Unlock()
+
+	for _, fn := range m.functions {
+		fn.Reset()
+	}
+}
+
+// Calls returns every recorded HostCall invocation in order, including calls
+// rejected by validation.
+func (m *Mock) Calls() []Call {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return slices.Clone(m.calls)
+}
+
+// Payloads returns the payload of every recorded HostCall invocation in order.
+func (m *Mock) Payloads() [][]byte {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	payloads := make([][]byte, 0, len(m.calls))
+	for _, call := range m.calls {
+		payloads = append(payloads, call.Payload)
+	}
+	return payloads
+}
+
+// AssertExpectations fails t when ExpectedCalls is set and differs from Count.
+func (m *Mock) AssertExpectations(t testing.TB) {
+	t.Helper()
+
+	if m.ExpectedCalls == 0 {
+		return
+	}
+	if got := m.Count(); got != m.ExpectedCalls {
+		t.Errorf("hostmock: expected %d host call(s), got %d", m.ExpectedCalls, got)
+	}
+}
+
 // HostCall simulates a host call, validating inputs and returning a response or error.
 func (m *Mock) HostCall(namespace, capability, function string, payload []byte) ([]byte, error) {
+	// Record the call before validation so failed expectations can be inspected.
+	m.mu.Lock()
+	m.calls = append(m.calls, Call{
+		Namespace:  namespace,
+		Capability: capability,
+		Function:   function,
+		Payload:    bytes.Clone(payload),
+	})
+	n := len(m.calls)
+	m.mu.Unlock()
+
+	// Simulate host latency before doing any work.
+	if m.Block != nil {
+		<-m.Block
+	}
+	if m.Delay > 0 {
+		clock := m.Clock
+		if clock == nil {
+			clock = systemClock{}
+		}
+		<-clock.After(m.Delay)
+	}
+
 	// Validate namespace when an expectation is supplied.
 	if m.ExpectedNamespace != "" && m.ExpectedNamespace != namespace {
 		return nil, fmt.Errorf(
@@ -107,6 +434,20 @@ func (m *Mock) HostCall(namespace, capability, function string, payload []byte)
 		return nil, fmt.Errorf("%w: expected function %s, got %s", ErrUnexpectedFunction, m.ExpectedFunction, function)
 	}
 
+	// Reject oversized payloads before any function or validator sees them.
+	if m.MaxPayloadBytes > 0 && len(payload) > m.MaxPayloadBytes {
+		return nil, fmt.Errorf("%w: %d bytes exceeds limit of %d", ErrPayloadTooLarge, len(payload), m.MaxPayloadBytes)
+	}
+
+	// Route to the registered function when per-function behaviour is configured.
+	if m.functions != nil {
+		fn, ok := m.functions[function]
+		if !ok {
+			return nil, fmt.Errorf("%w: no handler registered for %s", ErrUnexpectedFunction, function)
+		}
+		return fn.HostCall(namespace, capability, function, payload)
+	}
+
 	// Validate payload using user-defined validator, if provided
 	if m.PayloadValidator != nil {
 		if err := m.PayloadValidator(payload); err != nil {
@@ -114,18 +455,48 @@ func (m *Mock) HostCall(namespace, capability, function string, payload []byte)
 		}
 	}
 
+	resp, err := m.response()
+	if err != nil {
+		return resp, err
+	}
+
 	// Return configured response alongside failure when requested.
-	if m.Fail {
-		var resp []byte
-		if m.Response != nil {
-			resp = m.Response()
-		}
+	if m.failing(n) {
 		if m.Error != nil {
 			return resp, m.Error
 		}
 		return resp, ErrOperationFailed
 	}
 
+	return resp, nil
+}
+
+// failing reports whether the nth call, counting from one, should fail.
+func (m *Mock) failing(n int) bool {
+	switch {
+	case m.FailAfter > 0:
+		return n > m.FailAfter
+	case m.SucceedAfter > 0:
+		return n <= m.SucceedAfter
+	default:
+		return m.Fail
+	}
+}
+
+// response returns the next sequenced result, the single Response, or nil.
+func (m *Mock) response() ([]byte, error) {
+	if len(m.Responses) > 0 {
+		m.mu.Lock()
+		fn := m.Responses[min(m.next, len(m.Responses)-1)]
+		m.next++
+		m.mu.Unlock()
+
+		if fn == nil {
+			return nil, nil
+		}
+		return fn()
+	}
+
 	// Return user-defined response if provided
 	if m.Response != nil {
 		return m.Response(), nil
@@ -134,3 +505,8 @@ func (m *Mock) HostCall(namespace, capability, function string, payload []byte)
 	// Default to no response
 	return nil, nil
 }
+
+// After returns time.After(d).
+func (systemClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
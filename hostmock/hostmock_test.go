@@ -3,7 +3,10 @@ package hostmock
 import (
 	"bytes"
 	"errors"
+	"reflect"
+	"sync"
 	"testing"
+	"time"
 )
 
 type TestCase struct {
@@ -244,3 +247,694 @@ func TestHostMock(t *testing.T) {
 		})
 	}
 }
+
+func TestHostMockResponses(t *testing.T) {
+	mock, err := New(Config{
+		Responses: []func() ([]byte, error){
+			func() ([]byte, error) { return []byte("miss"), nil },
+			func() ([]byte, error) { return []byte("hit"), nil },
+		},
+	})
+	if err != nil {
+		t.Fatalf("New Mock instance creation failed: %v", err)
+	}
+
+	for i, want := range []string{"miss", "hit", "hit"} {
+		got, err := mock.HostCall("test", "test", "test", nil)
+		if err != nil {
+			t.Fatalf("call %d returned unexpected error: %v", i, err)
+		}
+		if string(got) != want {
+			t.Fatalf("call %d returned unexpected response: got %q, want %q", i, got, want)
+		}
+	}
+
+	t.Run("Flaky host fails then succeeds", func(t *testing.T) {
+		mock, err := New(Config{
+			Responses: []func() ([]byte, error){
+				func() ([]byte, error) { return nil, ErrMockError },
+				func() ([]byte, error) { return nil, ErrMockError },
+				func() ([]byte, error) { return []byte("ok"), nil },
+			},
+		})
+		if err != nil {
+			t.Fatalf("New Mock instance creation failed: %v", err)
+		}
+
+		wants := []struct {
+			resp []byte
+			err  error
+		}{
+			{err: ErrMockError},
+			{err: ErrMockError},
+			{resp: []byte("ok")},
+			{resp: []byte("ok")},
+		}
+		for i, want := range wants {
+			got, err := mock.HostCall("test", "test", "test", nil)
+			if !errors.Is(err, want.err) {
+				t.Fatalf("call %d returned unexpected error: got %v, want %v", i, err, want.err)
+			}
+			if !bytes.Equal(got, want.resp) {
+				t.Fatalf("call %d returned unexpected response: got %q, want %q", i, got, want.resp)
+			}
+		}
+	})
+
+	t.Run("Fail still advances sequence", func(t *testing.T) {
+		mock, err := New(Config{
+			Fail: true,
+			Responses: []func() ([]byte, error){
+				func() ([]byte, error) { return []byte("first"), nil },
+				nil,
+			},
+		})
+		if err != nil {
+			t.Fatalf("New Mock instance creation failed: %v", err)
+		}
+
+		got, err := mock.HostCall("test", "test", "test", nil)
+		if !errors.Is(err, ErrOperationFailed) || string(got) != "first" {
+			t.Fatalf("first call: got %q, %v", got, err)
+		}
+
+		got, err = mock.HostCall("test", "test", "test", nil)
+		if !errors.Is(err, ErrOperationFailed) || got != nil {
+			t.Fatalf("second call: got %q, %v", got, err)
+		}
+	})
+}
+
+func TestHostMockFailAfter(t *testing.T) {
+	tt := []struct {
+		name  string
+		cfg   Config
+		wants []bool
+	}{
+		{name: "FailAfter", cfg: Config{FailAfter: 2}, wants: []bool{false, false, true, true}},
+		{name: "SucceedAfter", cfg: Config{SucceedAfter: 2}, wants: []bool{true, true, false, false}},
+		{
+			name:  "Custom error",
+			cfg:   Config{FailAfter: 1, Error: ErrMockError},
+			wants: []bool{false, true},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.cfg.Response = func() []byte { return []byte("ok") }
+			mock, err := New(tc.cfg)
+			if err != nil {
+				t.Fatalf("New Mock instance creation failed: %v", err)
+			}
+
+			wantErr := ErrOperationFailed
+			if tc.cfg.Error != nil {
+				wantErr = tc.cfg.Error
+			}
+
+			for i, fail := range tc.wants {
+				got, err := mock.HostCall("test", "test", "test", nil)
+				if fail && !errors.Is(err, wantErr) {
+					t.Fatalf("call %d: expected %v, got %v", i+1, wantErr, err)
+				}
+				if !fail && err != nil {
+					t.Fatalf("call %d returned unexpected error: %v", i+1, err)
+				}
+				if string(got) != "ok" {
+					t.Fatalf("call %d returned unexpected response: %q", i+1, got)
+				}
+			}
+		})
+	}
+
+	t.Run("Reset restarts the count", func(t *testing.T) {
+		mock, err := New(Config{FailAfter: 1})
+		if err != nil {
+			t.Fatalf("New Mock instance creation failed: %v", err)
+		}
+
+		_, _ = mock.HostCall("test", "test", "test", nil)
+		if _, err := mock.HostCall("test", "test", "test", nil); !errors.Is(err, ErrOperationFailed) {
+			t.Fatalf("expected second call to fail, got %v", err)
+		}
+
+		mock.Reset()
+		if _, err := mock.HostCall("test", "test", "test", nil); err != nil {
+			t.Fatalf("expected first call after Reset to succeed, got %v", err)
+		}
+	})
+}
+
+func TestHostMockMaxPayloadBytes(t *testing.T) {
+	validated := 0
+	mock, err := New(Config{
+		MaxPayloadBytes: 4,
+		PayloadValidator: func([]byte) error {
+			validated++
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("New Mock instance creation failed: %v", err)
+	}
+
+	tt := []struct {
+		name    string
+		payload []byte
+		wantErr error
+	}{
+		{name: "Empty payload", payload: nil},
+		{name: "Under limit", payload: []byte("abc")},
+		{name: "At limit", payload: []byte("abcd")},
+		{name: "Over limit", payload: []byte("abcde"), wantErr: ErrPayloadTooLarge},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			before := validated
+			_, err := mock.HostCall("test", "test", "test", tc.payload)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("HostCall returned error %v, want %v", err, tc.wantErr)
+			}
+
+			wantValidated := before + 1
+			if tc.wantErr != nil {
+				wantValidated = before
+			}
+			if validated != wantValidated {
+				t.Fatalf("PayloadValidator ran %d time(s), want %d", validated-before, wantValidated-before)
+			}
+		})
+	}
+
+	t.Run("Applies before Functions", func(t *testing.T) {
+		mock, err := New(Config{
+			MaxPayloadBytes: 1,
+			Functions:       map[string]FunctionConfig{"set": {}},
+		})
+		if err != nil {
+			t.Fatalf("New Mock instance creation failed: %v", err)
+		}
+
+		if _, err := mock.HostCall("test", "test", "set", []byte("ab")); !errors.Is(err, ErrPayloadTooLarge) {
+			t.Fatalf("HostCall returned error %v, want %v", err, ErrPayloadTooLarge)
+		}
+	})
+}
+
+// recorder captures failures reported through testing.TB.
+type recorder struct {
+	testing.TB
+	failed bool
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(string, ...any) { r.failed = true }
+
+func TestUnexpected(t *testing.T) {
+	t.Run("Fails when called", func(t *testing.T) {
+		r := &recorder{TB: t}
+		hostCall := Unexpected(r)
+
+		if _, err := hostCall("test", "test", "test", nil); !errors.Is(err, ErrUnexpectedCall) {
+			t.Fatalf("HostCall returned error %v, want %v", err, ErrUnexpectedCall)
+		}
+		if !r.failed {
+			t.Fatalf("expected Unexpected to fail the test")
+		}
+	})
+
+	t.Run("Passes when not called", func(t *testing.T) {
+		r := &recorder{TB: t}
+		_ = Unexpected(r)
+
+		if r.failed {
+			t.Fatalf("expected Unexpected not to fail the test")
+		}
+	})
+}
+
+func TestHostMockExpectedCalls(t *testing.T) {
+	tt := []struct {
+		name     string
+		expected int
+		calls    int
+		wantFail bool
+	}{
+		{name: "Exact invocation count", expected: 1, calls: 1},
+		{name: "Under invocation", expected: 2, calls: 1, wantFail: true},
+		{name: "Over invocation", expected: 1, calls: 2, wantFail: true},
+		{name: "No expectation", expected: 0, calls: 3},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			mock, err := New(Config{ExpectedFunction: "test", ExpectedCalls: tc.expected})
+			if err != nil {
+				t.Fatalf("New Mock instance creation failed: %v", err)
+			}
+
+			for range tc.calls {
+				_, _ = mock.HostCall("test", "test", "test", nil)
+			}
+
+			if got := mock.Count(); got != tc.calls {
+				t.Fatalf("Count returned %d, want %d", got, tc.calls)
+			}
+
+			rec := &recorder{TB: t}
+			mock.AssertExpectations(rec)
+			if rec.failed != tc.wantFail {
+				t.Fatalf("AssertExpectations failed = %v, want %v", rec.failed, tc.wantFail)
+			}
+		})
+	}
+
+	t.Run("Rejected calls are counted", func(t *testing.T) {
+		mock, err := New(Config{ExpectedFunction: "expected"})
+		if err != nil {
+			t.Fatalf("New Mock instance creation failed: %v", err)
+		}
+
+		if _, err := mock.HostCall("test", "test", "other", nil); !errors.Is(err, ErrUnexpectedFunction) {
+			t.Fatalf("Mock call returned unexpected error: %v", err)
+		}
+		if got := mock.Count(); got != 1 {
+			t.Fatalf("Count returned %d, want 1", got)
+		}
+	})
+}
+
+func TestHostMockRecordedCalls(t *testing.T) {
+	mock, err := New(Config{ExpectedCapability: "kvstore"})
+	if err != nil {
+		t.Fatalf("New Mock instance creation failed: %v", err)
+	}
+
+	payload := []byte("first")
+	if _, err := mock.HostCall("tarmac", "kvstore", "set", payload); err != nil {
+		t.Fatalf("Mock call returned unexpected error: %v", err)
+	}
+	// Mutating the caller's buffer must not change the recorded payload.
+	payload[0] = 'F'
+
+	if _, err := mock.HostCall("tarmac", "other", "get", []byte("second")); !errors.Is(err, ErrUnexpectedCapability) {
+		t.Fatalf("Mock call returned unexpected error: %v", err)
+	}
+
+	want := []Call{
+		{Namespace: "tarmac", Capability: "kvstore", Function: "set", Payload: []byte("first")},
+		{Namespace: "tarmac", Capability: "other", Function: "get", Payload: []byte("second")},
+	}
+	if got := mock.Calls(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Calls returned %+v, want %+v", got, want)
+	}
+
+	payloads := mock.Payloads()
+	if len(payloads) != 2 || string(payloads[0]) != "first" || string(payloads[1]) != "second" {
+		t.Fatalf("Payloads returned %q", payloads)
+	}
+}
+
+func TestHostMockConcurrentCalls(t *testing.T) {
+	mock, err := New(Config{
+		Functions: map[string]FunctionConfig{
+			"get": {
+				Responses: []func() ([]byte, error){
+					func() ([]byte, error) { return []byte("miss"), nil },
+					func() ([]byte, error) { return []byte("hit"), nil },
+				},
+			},
+			"set": {},
+		},
+	})
+	if err != nil {
+		t.Fatalf("New Mock instance creation failed: %v", err)
+	}
+
+	const workers, perWorker = 8, 50
+
+	var wg sync.WaitGroup
+	for i := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			function := []string{"get", "set"}[i%2]
+			for range perWorker {
+				if _, err := mock.HostCall("test", "kvstore", function, []byte(function)); err != nil {
+					t.Errorf("HostCall returned unexpected error: %v", err)
+					return
+				}
+				_ = mock.Calls()
+				_ = mock.Payloads()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got, want := mock.Count(), workers*perWorker; got != want {
+		t.Fatalf("expected %d recorded calls, got %d", want, got)
+	}
+
+	calls := mock.Calls()
+	calls[0].Function = "mutated"
+	if mock.Calls()[0].Function == "mutated" {
+		t.Fatalf("Calls returned a slice sharing storage with the mock")
+	}
+}
+
+func TestHostMockFunctions(t *testing.T) {
+	mock, err := New(Config{
+		ExpectedNamespace:  "tarmac",
+		ExpectedCapability: "kvstore",
+		Functions: map[string]FunctionConfig{
+			"get": {
+				Response: func() []byte { return []byte("value") },
+			},
+			"set": {
+				PayloadValidator: func(payload []byte) error {
+					if string(payload) != "valid" {
+						return ErrMockError
+					}
+					return nil
+				},
+			},
+			"delete": {
+				Fail: true,
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("New Mock instance creation failed: %v", err)
+	}
+
+	tt := []struct {
+		name       string
+		capability string
+		function   string
+		payload    []byte
+		want       []byte
+		wantErr    error
+	}{
+		{name: "Routes get", capability: "kvstore", function: "get", want: []byte("value")},
+		{name: "Routes set", capability: "kvstore", function: "set", payload: []byte("valid")},
+		{
+			name:       "Validates per function",
+			capability: "kvstore",
+			function:   "set",
+			payload:    []byte("bad"),
+			wantErr:    ErrMockError,
+		},
+		{name: "Fails per function", capability: "kvstore", function: "delete", wantErr: ErrOperationFailed},
+		{name: "Unregistered function", capability: "kvstore", function: "keys", wantErr: ErrUnexpectedFunction},
+		{name: "Capability still enforced", capability: "other", function: "get", wantErr: ErrUnexpectedCapability},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := mock.HostCall("tarmac", tc.capability, tc.function, tc.payload)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("Mock call returned unexpected error: got %v, want %v", err, tc.wantErr)
+			}
+			if !bytes.Equal(got, tc.want) {
+				t.Fatalf("Mock call returned unexpected response: got %v, want %v", got, tc.want)
+			}
+		})
+	}
+
+	if got := mock.Count(); got != len(tt) {
+		t.Fatalf("Count returned %d, want %d", got, len(tt))
+	}
+}
+
+// fakeTimer is a pending fakeClock.After call.
+type fakeTimer struct {
+	d    time.Duration
+	fire chan time.Time
+}
+
+// fakeClock hands each After call to the test, which fires it explicitly.
+type fakeClock struct {
+	timers chan fakeTimer
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	fire := make(chan time.Time, 1)
+	c.timers <- fakeTimer{d: d, fire: fire}
+	return fire
+}
+
+func TestHostMockLatency(t *testing.T) {
+	t.Run("Delay", func(t *testing.T) {
+		mock, err := New(Config{Delay: 20 * time.Millisecond})
+		if err != nil {
+			t.Fatalf("New Mock instance creation failed: %v", err)
+		}
+
+		start := time.Now()
+		if _, err := mock.HostCall("test", "test", "test", nil); err != nil {
+			t.Fatalf("Mock call returned unexpected error: %v", err)
+		}
+		if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+			t.Fatalf("Mock call returned after %v, want at least %v", elapsed, 20*time.Millisecond)
+		}
+	})
+
+	t.Run("Delay with fake clock", func(t *testing.T) {
+		clock := &fakeClock{timers: make(chan fakeTimer, 1)}
+		mock, err := New(Config{Delay: time.Hour, Clock: clock, Response: func() []byte { return []byte("done") }})
+		if err != nil {
+			t.Fatalf("New Mock instance creation failed: %v", err)
+		}
+
+		result := make(chan []byte, 1)
+		go func() {
+			resp, _ := mock.HostCall("test", "test", "test", nil)
+			result <- resp
+		}()
+
+		timer := <-clock.timers
+		if timer.d != time.Hour {
+			t.Fatalf("Mock waited for %v, want %v", timer.d, time.Hour)
+		}
+		select {
+		case <-result:
+			t.Fatal("Mock call returned before the clock fired")
+		default:
+		}
+
+		timer.fire <- time.Time{}
+		if got := <-result; string(got) != "done" {
+			t.Fatalf("Mock call returned unexpected response: %q", got)
+		}
+	})
+
+	t.Run("Block", func(t *testing.T) {
+		block := make(chan struct{})
+		mock, err := New(Config{Block: block, Response: func() []byte { return []byte("done") }})
+		if err != nil {
+			t.Fatalf("New Mock instance creation failed: %v", err)
+		}
+
+		result := make(chan []byte, 1)
+		go func() {
+			resp, _ := mock.HostCall("test", "test", "test", nil)
+			result <- resp
+		}()
+
+		select {
+		case <-result:
+			t.Fatal("Mock call returned before Block was closed")
+		case <-time.After(20 * time.Millisecond):
+		}
+
+		close(block)
+		if got := <-result; string(got) != "done" {
+			t.Fatalf("Mock call returned unexpected response: %q", got)
+		}
+	})
+}
+
+func TestNewValidatesConfig(t *testing.T) {
+	response := func() []byte { return []byte("ok") }
+	responses := []func() ([]byte, error){func() ([]byte, error) { return nil, nil }}
+
+	tt := []struct {
+		name string
+		cfg  Config
+	}{
+		{name: "Response and Responses", cfg: Config{Response: response, Responses: responses}},
+		{name: "Negative ExpectedCalls", cfg: Config{ExpectedCalls: -1}},
+		{name: "Negative Delay", cfg: Config{Delay: -time.Second}},
+		{name: "Negative MaxPayloadBytes", cfg: Config{MaxPayloadBytes: -1}},
+		{name: "Negative FailAfter", cfg: Config{FailAfter: -1}},
+		{name: "Negative SucceedAfter", cfg: Config{SucceedAfter: -1}},
+		{name: "FailAfter and SucceedAfter", cfg: Config{FailAfter: 1, SucceedAfter: 1}},
+		{name: "Fail and FailAfter", cfg: Config{Fail: true, FailAfter: 1}},
+		{
+			name: "Functions with top-level FailAfter",
+			cfg:  Config{FailAfter: 1, Functions: map[string]FunctionConfig{"get": {}}},
+		},
+		{
+			name: "Functions with ExpectedFunction",
+			cfg:  Config{ExpectedFunction: "get", Functions: map[string]FunctionConfig{"get": {}}},
+		},
+		{
+			name: "Functions with top-level Response",
+			cfg:  Config{Response: response, Functions: map[string]FunctionConfig{"get": {}}},
+		},
+		{
+			name: "Functions with top-level Fail",
+			cfg:  Config{Fail: true, Functions: map[string]FunctionConfig{"get": {}}},
+		},
+		{
+			name: "Functions with empty name",
+			cfg:  Config{Functions: map[string]FunctionConfig{"": {}}},
+		},
+		{
+			name: "Function Response and Responses",
+			cfg:  Config{Functions: map[string]FunctionConfig{"get": {Response: response, Responses: responses}}},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			mock, err := New(tc.cfg)
+			if !errors.Is(err, ErrInvalidConfig) {
+				t.Fatalf("New returned error %v, want %v", err, ErrInvalidConfig)
+			}
+			if mock != nil {
+				t.Fatalf("New returned a mock for an invalid config")
+			}
+		})
+	}
+
+	t.Run("Fail with Response is allowed", func(t *testing.T) {
+		if _, err := New(Config{Fail: true, Error: ErrMockError, Response: response}); err != nil {
+			t.Fatalf("New returned unexpected error: %v", err)
+		}
+	})
+
+	t.Run("Error without Fail is allowed", func(t *testing.T) {
+		if _, err := New(Config{Error: ErrMockError}); err != nil {
+			t.Fatalf("New returned unexpected error: %v", err)
+		}
+	})
+}
+
+func TestHostMockWildcards(t *testing.T) {
+	tt := []struct {
+		name    string
+		cfg     Config
+		call    [3]string
+		wantErr error
+	}{
+		{
+			name: "Blank namespace matches any",
+			cfg:  Config{ExpectedCapability: "cap", ExpectedFunction: "fn"},
+			call: [3]string{"anything", "cap", "fn"},
+		},
+		{
+			name: "Blank capability matches any",
+			cfg:  Config{ExpectedNamespace: "ns", ExpectedFunction: "fn"},
+			call: [3]string{"ns", "anything", "fn"},
+		},
+		{
+			name: "Blank function matches any",
+			cfg:  Config{ExpectedNamespace: "ns", ExpectedCapability: "cap"},
+			call: [3]string{"ns", "cap", "anything"},
+		},
+		{
+			name: "Blank expectations match empty values",
+			cfg:  Config{},
+			call: [3]string{"", "", ""},
+		},
+		{
+			name:    "Set namespace enforces",
+			cfg:     Config{ExpectedNamespace: "ns"},
+			call:    [3]string{"other", "cap", "fn"},
+			wantErr: ErrUnexpectedNamespace,
+		},
+		{
+			name:    "Set capability enforces",
+			cfg:     Config{ExpectedCapability: "cap"},
+			call:    [3]string{"ns", "other", "fn"},
+			wantErr: ErrUnexpectedCapability,
+		},
+		{
+			name:    "Set function enforces",
+			cfg:     Config{ExpectedFunction: "fn"},
+			call:    [3]string{"ns", "cap", "other"},
+			wantErr: ErrUnexpectedFunction,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			mock, err := New(tc.cfg)
+			if err != nil {
+				t.Fatalf("New Mock instance creation failed: %v", err)
+			}
+
+			_, err = mock.HostCall(tc.call[0], tc.call[1], tc.call[2], nil)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("Mock call returned unexpected error: got %v, want %v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestHostMockReset(t *testing.T) {
+	mock, err := New(Config{
+		ExpectedCalls: 1,
+		Responses: []func() ([]byte, error){
+			func() ([]byte, error) { return []byte("first"), nil },
+			func() ([]byte, error) { return []byte("second"), nil },
+		},
+	})
+	if err != nil {
+		t.Fatalf("New Mock instance creation failed: %v", err)
+	}
+
+	routed, err := New(Config{
+		Functions: map[string]FunctionConfig{
+			"get": {
+				Responses: []func() ([]byte, error){
+					func() ([]byte, error) { return []byte("miss"), nil },
+					func() ([]byte, error) { return []byte("hit"), nil },
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("New Mock instance creation failed: %v", err)
+	}
+
+	for _, name := range []string{"first run", "second run"} {
+		t.Run(name, func(t *testing.T) {
+			mock.Reset()
+			routed.Reset()
+
+			if got := mock.Count(); got != 0 {
+				t.Fatalf("Count after Reset returned %d, want 0", got)
+			}
+
+			got, err := mock.HostCall("test", "test", "test", []byte("payload"))
+			if err != nil || string(got) != "first" {
+				t.Fatalf("Mock call after Reset: got %q, %v", got, err)
+			}
+			mock.AssertExpectations(t)
+
+			if payloads := mock.Payloads(); len(payloads) != 1 {
+				t.Fatalf("Payloads after Reset returned %q, want one entry", payloads)
+			}
+
+			got, err = routed.HostCall("test", "test", "get", nil)
+			if err != nil || string(got) != "miss" {
+				t.Fatalf("Routed call after Reset: got %q, %v", got, err)
+			}
+		})
+	}
+}
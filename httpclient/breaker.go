@@ -0,0 +1,151 @@
+package httpclient
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	sdk "github.com/tarmac-project/sdk"
+)
+
+var (
+	// ErrCircuitOpen indicates a request was rejected without a host call
+	// because the circuit breaker is open.
+	ErrCircuitOpen = errors.New("circuit breaker is open")
+
+	// ErrInvalidBreaker indicates a circuit breaker threshold or cooldown that
+	// is not positive.
+	ErrInvalidBreaker = errors.New("circuit breaker threshold and cooldown must be positive")
+)
+
+// BreakerState is the state of a CircuitBreaker.
+type BreakerState int
+
+const (
+	// BreakerClosed lets every request through and counts consecutive failures.
+	BreakerClosed BreakerState = iota
+
+	// BreakerOpen rejects every request with ErrCircuitOpen until the cooldown
+	// has passed.
+	BreakerOpen
+
+	// BreakerHalfOpen lets a single trial request through; its outcome closes
+	// or reopens the breaker.
+	BreakerHalfOpen
+)
+
+// String returns the lower-case name of the state.
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerClosed:
+		return "closed"
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// CircuitBreaker stops a client from calling a failing upstream. After
+// threshold consecutive failures it opens and fast-fails requests with
+// ErrCircuitOpen. Once cooldown has passed it half-opens and lets one trial
+// request through: success closes the breaker, failure reopens it for another
+// cooldown.
+//
+// A failure is any request that returns an error or a 5xx status code. A
+// CircuitBreaker is safe for concurrent use and may be shared by clients that
+// call the same upstream.
+type CircuitBreaker struct {
+	threshold int
+	cooldown  time.Duration
+
+	// clock supplies the current time.
+	clock sdk.Clock
+
+	mu       sync.Mutex
+	state    BreakerState
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+// NewCircuitBreaker returns a closed breaker that opens after threshold
+// consecutive failures and stays open for cooldown.
+func NewCircuitBreaker(threshold int, cooldown time.Duration) (*CircuitBreaker, error) {
+	return NewCircuitBreakerWithClock(threshold, cooldown, sdk.SystemClock)
+}
+
+// NewCircuitBreakerWithClock is NewCircuitBreaker with cooldowns measured by
+// clock, so tests can advance time without waiting. A nil clock uses
+// sdk.SystemClock.
+func NewCircuitBreakerWithClock(threshold int, cooldown time.Duration, clock sdk.Clock) (*CircuitBreaker, error) {
+	if threshold < 1 || cooldown <= 0 {
+		return nil, ErrInvalidBreaker
+	}
+
+	if clock == nil {
+		clock = sdk.SystemClock
+	}
+
+	return &CircuitBreaker{threshold: threshold, cooldown: cooldown, clock: clock}, nil
+}
+
+// State returns the current state, reporting BreakerHalfOpen once an open
+// breaker's cooldown has passed.
+func (b *CircuitBreaker) State() BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.advance()
+	return b.state
+}
+
+// allow reports whether a request may proceed, reserving the trial request
+// when the breaker is half-open.
+func (b *CircuitBreaker) allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.advance()
+	switch b.state {
+	case BreakerOpen:
+		return ErrCircuitOpen
+	case BreakerHalfOpen:
+		if b.probing {
+			return ErrCircuitOpen
+		}
+		b.probing = true
+	}
+
+	return nil
+}
+
+// record updates the breaker with the outcome of an allowed request.
+func (b *CircuitBreaker) record(failed bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+	if !failed {
+		b.state = BreakerClosed
+		b.failures = 0
+		return
+	}
+
+	b.failures++
+	if b.state == BreakerHalfOpen || b.failures >= b.threshold {
+		b.state = BreakerOpen
+		b.openedAt = b.clock.Now()
+	}
+}
+
+// advance moves an open breaker to half-open once its cooldown has passed.
+// Callers must hold mu.
+func (b *CircuitBreaker) advance() {
+	if b.state == BreakerOpen && b.clock.Now().Sub(b.openedAt) >= b.cooldown {
+		b.state = BreakerHalfOpen
+		b.failures = 0
+	}
+}
@@ -0,0 +1,169 @@
+package httpclient
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	sdkproto "github.com/tarmac-project/protobuf-go/sdk"
+	proto "github.com/tarmac-project/protobuf-go/sdk/http"
+	sdk "github.com/tarmac-project/sdk"
+	"github.com/tarmac-project/sdk/hostmock"
+)
+
+// fakeClock is an sdk.Clock whose time only moves when Advance is called. The
+// breaker and cookie jar only read Now, so After fires immediately.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	ch := make(chan time.Time, 1)
+	ch <- c.Now().Add(d)
+	return ch
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// codeResponse returns a host response carrying the HTTP status code.
+func codeResponse(code int32) func() ([]byte, error) {
+	return func() ([]byte, error) {
+		return (&proto.HTTPClientResponse{Status: &sdkproto.Status{Status: "OK", Code: 200}, Code: code}).MarshalVT()
+	}
+}
+
+func TestNewCircuitBreaker(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		name      string
+		threshold int
+		cooldown  time.Duration
+		wantErr   error
+	}{
+		{name: "valid", threshold: 3, cooldown: time.Second},
+		{name: "zero threshold", threshold: 0, cooldown: time.Second, wantErr: ErrInvalidBreaker},
+		{name: "zero cooldown", threshold: 3, wantErr: ErrInvalidBreaker},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			b, err := NewCircuitBreaker(tc.threshold, tc.cooldown)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("expected error %v, got %v", tc.wantErr, err)
+			}
+			if err == nil && b.State() != BreakerClosed {
+				t.Fatalf("expected a new breaker to be closed, got %s", b.State())
+			}
+		})
+	}
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	t.Parallel()
+
+	hostErr := errors.New("upstream unavailable")
+	mock, err := hostmock.New(hostmock.Config{
+		Responses: []func() ([]byte, error){
+			func() ([]byte, error) { return nil, hostErr },
+			codeResponse(503),
+			codeResponse(500),
+			codeResponse(200),
+			codeResponse(200),
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to create hostmock: %v", err)
+	}
+
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	breaker, err := NewCircuitBreakerWithClock(2, time.Minute, clock)
+	if err != nil {
+		t.Fatalf("NewCircuitBreakerWithClock returned error: %v", err)
+	}
+
+	client, err := New(Config{HostCall: mock.HostCall, CircuitBreaker: breaker})
+	if err != nil {
+		t.Fatalf("New returned error: %v", err)
+	}
+
+	steps := []struct {
+		name      string
+		advance   time.Duration
+		wantErr   error
+		wantState BreakerState
+		wantCalls int
+	}{
+		{name: "host failure counts", wantErr: sdk.ErrHostCall, wantState: BreakerClosed, wantCalls: 1},
+		{name: "5xx opens at threshold", wantState: BreakerOpen, wantCalls: 2},
+		{name: "open fast-fails", wantErr: ErrCircuitOpen, wantState: BreakerOpen, wantCalls: 2},
+		{name: "failed trial reopens", advance: time.Minute, wantState: BreakerOpen, wantCalls: 3},
+		{
+			name:      "still cooling down",
+			advance:   time.Second,
+			wantErr:   ErrCircuitOpen,
+			wantState: BreakerOpen,
+			wantCalls: 3,
+		},
+		{name: "successful trial closes", advance: time.Minute, wantState: BreakerClosed, wantCalls: 4},
+		{name: "closed passes through", wantState: BreakerClosed, wantCalls: 5},
+	}
+
+	for _, step := range steps {
+		clock.Advance(step.advance)
+
+		_, err := client.Get("http://example.com")
+		if !errors.Is(err, step.wantErr) {
+			t.Fatalf("%s: expected error %v, got %v", step.name, step.wantErr, err)
+		}
+		if got := breaker.State(); got != step.wantState {
+			t.Fatalf("%s: expected state %s, got %s", step.name, step.wantState, got)
+		}
+		if got := mock.Count(); got != step.wantCalls {
+			t.Fatalf("%s: expected %d host calls, got %d", step.name, step.wantCalls, got)
+		}
+	}
+}
+
+func TestCircuitBreakerHalfOpen(t *testing.T) {
+	t.Parallel()
+
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	breaker, err := NewCircuitBreakerWithClock(1, time.Minute, clock)
+	if err != nil {
+		t.Fatalf("NewCircuitBreakerWithClock returned error: %v", err)
+	}
+
+	breaker.record(true)
+	clock.Advance(time.Minute)
+	if got := breaker.State(); got != BreakerHalfOpen {
+		t.Fatalf("expected %s after cooldown, got %s", BreakerHalfOpen, got)
+	}
+
+	// Only one trial request may be in flight while half-open.
+	if err := breaker.allow(); err != nil {
+		t.Fatalf("expected the trial request to be allowed, got %v", err)
+	}
+	if err := breaker.allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected a concurrent request to fail with %v, got %v", ErrCircuitOpen, err)
+	}
+
+	breaker.record(false)
+	if got := breaker.State(); got != BreakerClosed {
+		t.Fatalf("expected %s after a successful trial, got %s", BreakerClosed, got)
+	}
+}
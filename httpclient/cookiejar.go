@@ -0,0 +1,160 @@
+package httpclient
+
+import (
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	sdk "github.com/tarmac-project/sdk"
+)
+
+// CookieJar is a simple in-memory http.CookieJar for use with Config.CookieJar.
+//
+// Cookies are scoped to the exact host that set them; Domain attributes are
+// ignored, so cookies are never shared with subdomains. Path, Secure, Expires,
+// and Max-Age are honored. A CookieJar is safe for concurrent use.
+type CookieJar struct {
+	// clock supplies the current time for expiry checks.
+	clock sdk.Clock
+
+	mu sync.Mutex
+
+	// entries maps a lower-cased host to its cookies keyed by name and path.
+	entries map[string]map[string]*http.Cookie
+}
+
+// Ensure CookieJar satisfies http.CookieJar at compile time.
+var _ http.CookieJar = (*CookieJar)(nil)
+
+// NewCookieJar returns an empty in-memory cookie jar.
+func NewCookieJar() *CookieJar {
+	return NewCookieJarWithClock(sdk.SystemClock)
+}
+
+// NewCookieJarWithClock returns an empty cookie jar that expires cookies by
+// clock. A nil clock uses sdk.SystemClock.
+func NewCookieJarWithClock(clock sdk.Clock) *CookieJar {
+	if clock == nil {
+		clock = sdk.SystemClock
+	}
+
+	return &CookieJar{clock: clock, entries: make(map[string]map[string]*http.Cookie)}
+}
+
+// SetCookies stores cookies received in a response from u. A cookie with a
+// negative Max-Age or an Expires time in the past removes any stored cookie
+// with the same name and path.
+func (j *CookieJar) SetCookies(u *url.URL, cookies []*http.Cookie) {
+	if u == nil || len(cookies) == 0 {
+		return
+	}
+
+	host := strings.ToLower(u.Hostname())
+	now := j.clock.Now()
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	stored := j.entries[host]
+	if stored == nil {
+		stored = make(map[string]*http.Cookie)
+		j.entries[host] = stored
+	}
+
+	for _, c := range cookies {
+		if c == nil || c.Name == "" {
+			continue
+		}
+
+		path := c.Path
+		if !strings.HasPrefix(path, "/") {
+			path = defaultCookiePath(u.EscapedPath())
+		}
+		key := c.Name + ";" + path
+
+		// Expired cookies are a request from the server to delete them.
+		if c.MaxAge < 0 || (!c.Expires.IsZero() && !c.Expires.After(now)) {
+			delete(stored, key)
+			continue
+		}
+
+		entry := &http.Cookie{Name: c.Name, Value: c.Value, Path: path, Secure: c.Secure, Expires: c.Expires}
+		if c.MaxAge > 0 {
+			entry.Expires = now.Add(time.Duration(c.MaxAge) * time.Second)
+		}
+		stored[key] = entry
+	}
+}
+
+// Cookies returns the unexpired cookies to send in a request to u, ordered by
+// longest path first and then by name.
+func (j *CookieJar) Cookies(u *url.URL) []*http.Cookie {
+	if u == nil {
+		return nil
+	}
+
+	host := strings.ToLower(u.Hostname())
+	path := u.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	now := j.clock.Now()
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	var matched []*http.Cookie
+	for key, c := range j.entries[host] {
+		if !c.Expires.IsZero() && !c.Expires.After(now) {
+			delete(j.entries[host], key)
+			continue
+		}
+		if c.Secure && u.Scheme != "https" {
+			continue
+		}
+		if !cookiePathMatch(path, c.Path) {
+			continue
+		}
+		matched = append(matched, c)
+	}
+
+	slices.SortFunc(matched, func(a, b *http.Cookie) int {
+		if len(a.Path) != len(b.Path) {
+			return len(b.Path) - len(a.Path)
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	out := make([]*http.Cookie, 0, len(matched))
+	for _, c := range matched {
+		out = append(out, &http.Cookie{Name: c.Name, Value: c.Value})
+	}
+
+	return out
+}
+
+// defaultCookiePath returns the default cookie path for a request path as
+// described in RFC 6265 section 5.1.4.
+func defaultCookiePath(requestPath string) string {
+	i := strings.LastIndex(requestPath, "/")
+	if i <= 0 {
+		return "/"
+	}
+
+	return requestPath[:i]
+}
+
+// cookiePathMatch reports whether requestPath falls under cookiePath as
+// described in RFC 6265 section 5.1.4.
+func cookiePathMatch(requestPath, cookiePath string) bool {
+	if !strings.HasPrefix(requestPath, cookiePath) {
+		return false
+	}
+
+	return len(requestPath) == len(cookiePath) ||
+		strings.HasSuffix(cookiePath, "/") ||
+		requestPath[len(cookiePath)] == '/'
+}
@@ -0,0 +1,182 @@
+package httpclient
+
+import (
+	"net/http"
+	"net/url"
+	"slices"
+	"testing"
+	"time"
+
+	sdkproto "github.com/tarmac-project/protobuf-go/sdk"
+	proto "github.com/tarmac-project/protobuf-go/sdk/http"
+	"github.com/tarmac-project/sdk/hostmock"
+)
+
+func TestCookieJar(t *testing.T) {
+	t.Parallel()
+
+	mustParse := func(raw string) *url.URL {
+		u, err := url.Parse(raw)
+		if err != nil {
+			t.Fatalf("failed to parse %q: %v", raw, err)
+		}
+		return u
+	}
+
+	names := func(cookies []*http.Cookie) []string {
+		out := make([]string, 0, len(cookies))
+		for _, c := range cookies {
+			out = append(out, c.Name+"="+c.Value)
+		}
+		return out
+	}
+
+	jar := NewCookieJar()
+	jar.SetCookies(mustParse("https://example.com/api/login"), []*http.Cookie{
+		{Name: "session", Value: "abc", Path: "/"},
+		{Name: "scoped", Value: "1"},
+		{Name: "secure", Value: "s", Path: "/", Secure: true},
+		{Name: "gone", Value: "x", Path: "/", MaxAge: -1},
+	})
+
+	tt := []struct {
+		name string
+		url  string
+		want []string
+	}{
+		{
+			name: "default path",
+			url:  "https://example.com/api/items",
+			want: []string{"scoped=1", "secure=s", "session=abc"},
+		},
+		{name: "outside default path", url: "https://example.com/apiv2", want: []string{"secure=s", "session=abc"}},
+		{name: "secure needs https", url: "http://example.com/", want: []string{"session=abc"}},
+		{name: "host is case-insensitive", url: "https://EXAMPLE.com/", want: []string{"secure=s", "session=abc"}},
+		{name: "other host", url: "https://other.example.com/api/items", want: []string{}},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := names(jar.Cookies(mustParse(tc.url))); !slices.Equal(got, tc.want) {
+				t.Fatalf("cookies mismatch: want %q, got %q", tc.want, got)
+			}
+		})
+	}
+
+	t.Run("negative max age deletes", func(t *testing.T) {
+		u := mustParse("https://delete.example.com/")
+		jar.SetCookies(u, []*http.Cookie{{Name: "session", Value: "abc"}})
+		jar.SetCookies(u, []*http.Cookie{{Name: "session", MaxAge: -1}})
+
+		if got := jar.Cookies(u); len(got) != 0 {
+			t.Fatalf("expected no cookies, got %q", names(got))
+		}
+	})
+}
+
+func TestCookieJarExpiry(t *testing.T) {
+	t.Parallel()
+
+	u, err := url.Parse("https://example.com/")
+	if err != nil {
+		t.Fatalf("failed to parse URL: %v", err)
+	}
+
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	jar := NewCookieJarWithClock(clock)
+
+	jar.SetCookies(u, []*http.Cookie{
+		{Name: "short", Value: "1", MaxAge: 60},
+		{Name: "long", Value: "2", Expires: clock.Now().Add(time.Hour)},
+	})
+
+	steps := []struct {
+		advance time.Duration
+		want    int
+	}{
+		{advance: 0, want: 2},
+		{advance: 59 * time.Second, want: 2},
+		{advance: time.Second, want: 1},
+		{advance: time.Hour, want: 0},
+	}
+
+	for i, step := range steps {
+		clock.Advance(step.advance)
+		if got := jar.Cookies(u); len(got) != step.want {
+			t.Fatalf("step %d: expected %d cookies, got %d", i, step.want, len(got))
+		}
+	}
+}
+
+func TestHTTPClientCookieJar(t *testing.T) {
+	t.Parallel()
+
+	response := func(setCookies ...string) func() ([]byte, error) {
+		return func() ([]byte, error) {
+			resp := &proto.HTTPClientResponse{
+				Status:  &sdkproto.Status{Status: "OK", Code: 200},
+				Code:    200,
+				Headers: map[string]*proto.Header{},
+			}
+			if len(setCookies) > 0 {
+				resp.Headers["Set-Cookie"] = &proto.Header{Values: setCookies}
+			}
+			return resp.MarshalVT()
+		}
+	}
+
+	mock, err := hostmock.New(hostmock.Config{
+		Responses: []func() ([]byte, error){
+			response("session=abc; Path=/", "theme=dark; Path=/"),
+			response(),
+			response(),
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to create hostmock: %v", err)
+	}
+
+	client, err := New(Config{HostCall: mock.HostCall, CookieJar: NewCookieJar()})
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	if _, err := client.Post("http://example.com/login", "text/plain", nil); err != nil {
+		t.Fatalf("login request returned error: %v", err)
+	}
+
+	req, err := NewRequest("GET", "http://example.com/profile", nil)
+	if err != nil {
+		t.Fatalf("NewRequest returned error: %v", err)
+	}
+	req.Header.Set("Cookie", "local=1")
+	if _, err := client.Do(req); err != nil {
+		t.Fatalf("profile request returned error: %v", err)
+	}
+
+	if _, err := client.Get("http://other.example.com/"); err != nil {
+		t.Fatalf("other host request returned error: %v", err)
+	}
+
+	cookieHeader := func(payload []byte) []string {
+		var sent proto.HTTPClient
+		if err := sent.UnmarshalVT(payload); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+		return sent.GetHeaders()["Cookie"].GetValues()
+	}
+
+	payloads := mock.Payloads()
+	if len(payloads) != 3 {
+		t.Fatalf("expected 3 host calls, got %d", len(payloads))
+	}
+	if got := cookieHeader(payloads[0]); len(got) != 0 {
+		t.Fatalf("expected no cookies on first request, got %q", got)
+	}
+	if got, want := cookieHeader(payloads[1]), []string{"local=1; session=abc; theme=dark"}; !slices.Equal(got, want) {
+		t.Fatalf("cookie header mismatch: want %q, got %q", want, got)
+	}
+	if got := cookieHeader(payloads[2]); len(got) != 0 {
+		t.Fatalf("expected no cookies for another host, got %q", got)
+	}
+}
@@ -3,7 +3,42 @@ Package httpclient provides an HTTP client for Tarmac WebAssembly functions.
 
 Requests are serialized via protobuf and sent to the host using waPC. The
 Client interface offers convenience methods (Get, Post, Put, Delete) and a Do
-method for custom requests. Errors use sentinel values combined with the
-underlying cause and can be checked with errors.Is.
+method for custom requests. PostBytes and PutBytes send an in-memory body
+without wrapping it in an io.Reader. Stat issues a HEAD and returns only the
+headers and status code, discarding any body the host sends. Ping checks that
+the capability is available without making an HTTP request. JoinPath builds
+request URLs from a base and percent-encoded path segments. Config.Timeout,
+defaulting to the SDK DefaultTimeout, bounds each host call.
+Response.ContentLength reports the declared body length, and a body shorter
+than declared is returned with ErrBodyTruncated. Response.DecodeJSON decodes
+and closes a JSON body, and Response.JSONLines iterates over a
+newline-delimited JSON body one record at a time. NewResponse and JSONResponse
+build responses shaped like the client's own, for tests and fakes. Errors use
+sentinel values combined with the underlying cause and can be checked with
+errors.Is.
+
+Request URLs must use the http or https scheme, or carry a host with no scheme;
+other schemes such as ftp, file, or data are rejected with ErrInvalidURL by
+every method before any host call.
+
+Config.BaseURL lets callers pass relative URLs, which are resolved against it
+with url.ResolveReference; absolute URLs bypass the base. Config.CookieJar
+persists cookies across requests; NewCookieJar provides a simple in-memory jar
+that scopes cookies to the host that set them. Config.PoolResponseBodies
+recycles response body buffers for hot paths; a pooled body must not be used
+after it is closed.
+
+Config.CircuitBreaker stops a client from hammering a failing upstream. After a
+run of consecutive failures, errors or 5xx responses, requests fail fast with
+ErrCircuitOpen until the cooldown passes and a trial request succeeds.
+CircuitBreaker.State reports the current state for observability.
+NewCircuitBreakerWithClock and NewCookieJarWithClock take an sdk.Clock so tests
+can advance cooldowns and cookie expiry without waiting.
+
+When the SDK RuntimeConfig carries a RequestID, it is sent as RequestIDHeader on
+every request that does not already set that header.
+
+The httpclienttest package builds canned host responses for tests that script
+the host through Config.HostCall or hostmock.
 */
 package httpclient
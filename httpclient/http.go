@@ -1,18 +1,31 @@
 package httpclient
 
 import (
+	"bufio"
 	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"iter"
 	"net/http"
 	"net/url"
+	"strconv"
+	"strings"
+	"time"
 
+	sdkproto "github.com/tarmac-project/protobuf-go/sdk"
 	proto "github.com/tarmac-project/protobuf-go/sdk/http"
 	sdk "github.com/tarmac-project/sdk"
 	wapc "github.com/wapc/wapc-guest-tinygo"
 )
 
+// capabilityName is the default host capability name for HTTP requests.
+const capabilityName = "httpclient"
+
+// RequestIDHeader carries sdk.RuntimeConfig.RequestID on outgoing requests.
+const RequestIDHeader = "X-Request-Id"
+
 // Client provides an interface for making HTTP requests.
 type Client interface {
 	// Get issues a GET request to the specified URL.
@@ -24,11 +37,27 @@ type Client interface {
 	// Put issues a PUT request to the specified URL with the given content type and body.
 	Put(url, contentType string, body io.Reader) (*Response, error)
 
+	// PostBytes issues a POST request with a byte slice body, avoiding the io.Reader copy.
+	PostBytes(url, contentType string, body []byte) (*Response, error)
+
+	// PutBytes issues a PUT request with a byte slice body, avoiding the io.Reader copy.
+	PutBytes(url, contentType string, body []byte) (*Response, error)
+
 	// Delete issues a DELETE request to the specified URL.
 	Delete(url string) (*Response, error)
 
+	// Stat issues a HEAD request to the specified URL and returns the response
+	// headers and status code.
+	Stat(url string) (http.Header, int, error)
+
 	// Do issues a custom HTTP request and returns the response.
 	Do(req *Request) (*Response, error)
+
+	// Ping checks that the host httpclient capability is available.
+	Ping() error
+
+	// Close releases resources held by the client.
+	Close() error
 }
 
 // Config configures the HTTP client behavior and host integration.
@@ -37,7 +66,10 @@ type Client interface {
 // Namespace is empty, it defaults to sdk.DefaultNamespace during New.
 // InsecureSkipVerify controls TLS verification behavior on the host side when
 // supported by the runtime. HostCall allows tests to inject a custom host
-// function; when nil, the client uses wapc.HostCall.
+// function; when nil, the client uses wapc.HostCall. Timeout bounds each host
+// call and falls back to SDKConfig.DefaultTimeout when zero. Capability
+// overrides the host capability name and defaults to "httpclient". CookieJar
+// persists cookies across requests when set.
 type Config struct {
 	// SDKConfig provides the runtime namespace for host calls.
 	SDKConfig sdk.RuntimeConfig
@@ -45,6 +77,23 @@ type Config struct {
 	InsecureSkipVerify bool
 	// HostCall overrides the waPC host function used for requests.
 	HostCall func(string, string, string, []byte) ([]byte, error)
+	// Timeout bounds each host call; a negative value disables the default.
+	Timeout time.Duration
+	// Capability overrides the host capability name used for host calls.
+	Capability string
+	// CookieJar, when set, supplies Cookie headers for outgoing requests and
+	// stores Set-Cookie headers from responses. See NewCookieJar.
+	CookieJar http.CookieJar
+	// PoolResponseBodies reads response bodies into buffers that are recycled
+	// when Response.Body is closed. Callers must not use the body after Close;
+	// bodies that are never closed are simply not reused.
+	PoolResponseBodies bool
+	// BaseURL, when set, is an absolute http or https URL that relative
+	// request URLs are resolved against. Absolute request URLs bypass it.
+	BaseURL string
+	// CircuitBreaker, when set, fast-fails requests with ErrCircuitOpen after
+	// repeated failures. See NewCircuitBreaker.
+	CircuitBreaker *CircuitBreaker
 }
 
 // HTTPClient implements Client using waPC host calls.
@@ -53,25 +102,66 @@ type HTTPClient struct {
 	cfg Config
 	// hostCall performs the waPC invocation; tests may override it.
 	hostCall func(string, string, string, []byte) ([]byte, error)
+	// baseURL is the parsed Config.BaseURL, or nil when unset.
+	baseURL *url.URL
 }
 
 // Ensure HTTPClient always satisfies the Client interface at compile time.
 var _ Client = (*HTTPClient)(nil)
 
-// doHTTPCall marshals the protobuf request, performs the host call, and
-// unmarshals the response into a Response using proto getters.
+// doHTTPCall performs req through the circuit breaker, when one is
+// configured, and records the outcome.
 func (c *HTTPClient) doHTTPCall(req *proto.HTTPClient) (*Response, error) {
-	b, err := req.MarshalVT()
+	breaker := c.cfg.CircuitBreaker
+	if breaker == nil {
+		return c.roundTrip(req)
+	}
+
+	if err := breaker.allow(); err != nil {
+		return &Response{}, err
+	}
+
+	resp, err := c.roundTrip(req)
+	breaker.record(err != nil || resp.StatusCode >= http.StatusInternalServerError)
+
+	return resp, err
+}
+
+// roundTrip marshals the protobuf request, performs the host call, and
+// unmarshals the response into a Response using proto getters.
+func (c *HTTPClient) roundTrip(req *proto.HTTPClient) (*Response, error) {
+	if id := c.cfg.SDKConfig.RequestID; id != "" {
+		addRequestID(req, id)
+	}
+
+	var jarURL *url.URL
+	if c.cfg.CookieJar != nil {
+		jarURL = c.addCookies(req)
+	}
+
+	b, err := sdk.MarshalRequest(req)
 	if err != nil {
-		return &Response{}, errors.Join(ErrMarshalRequest, err)
+		return &Response{}, err
 	}
 
-	resp, err := c.hostCall(c.cfg.SDKConfig.Namespace, "httpclient", "call", b)
+	resp, err := c.call(b)
 	if err != nil {
-		return &Response{}, errors.Join(sdk.ErrHostCall, err)
+		return &Response{}, sdk.HostCallError(err)
 	}
 
+	// Unmarshal into a recycled buffer when pooling so the body reuses its capacity.
 	var r proto.HTTPClientResponse
+	var buf *[]byte
+	if c.cfg.PoolResponseBodies {
+		buf = bodyPool.Get().(*[]byte)
+		r.Body = (*buf)[:0]
+		defer func() {
+			if buf != nil {
+				bodyPool.Put(buf)
+			}
+		}()
+	}
+
 	if unmarshalErr := r.UnmarshalVT(resp); unmarshalErr != nil {
 		return &Response{}, errors.Join(ErrUnmarshalResponse, unmarshalErr)
 	}
@@ -81,53 +171,268 @@ func (c *HTTPClient) doHTTPCall(req *proto.HTTPClient) (*Response, error) {
 		return &Response{}, sdk.ErrHostResponseInvalid
 	}
 
-	statusCode := status.GetCode()
-	switch statusCode {
-	case hostStatusOK, hostStatusPartial:
-		// success path continues
-	case hostStatusBadInput, hostStatusMissing, hostStatusError:
-		detail := fmt.Sprintf("host status %d", statusCode)
-		if msg := status.GetStatus(); msg != "" {
-			detail = fmt.Sprintf("%s: %s", detail, msg)
-		}
-		return &Response{}, errors.Join(sdk.ErrHostError, errors.New(detail))
-	default:
-		return &Response{}, errors.Join(
-			sdk.ErrHostResponseInvalid,
-			fmt.Errorf("unexpected host status code %d", statusCode),
-		)
+	if err := sdk.StatusToError(status.GetCode(), status.GetStatus()); err != nil {
+		return &Response{}, err
 	}
 
 	httpCode := int(r.GetCode())
-	statusText := http.StatusText(httpCode)
-
-	out := &Response{
-		Status:     statusText,
-		StatusCode: httpCode,
-		Header:     make(http.Header),
-	}
+	out := NewResponse(httpCode, nil, nil)
 
+	// Canonicalize names so Header.Get and Header.Values find host headers
+	// regardless of the case the host used.
 	for name, header := range r.GetHeaders() {
-		out.Header[name] = header.GetValues()
+		key := http.CanonicalHeaderKey(name)
+		out.Header[key] = append(out.Header[key], header.GetValues()...)
 	}
 
 	if body := r.GetBody(); len(body) > 0 {
-		out.Body = io.NopCloser(bytes.NewReader(body))
+		if buf != nil {
+			out.Body = newPooledBody(buf, body)
+			buf = nil
+		} else {
+			out.Body = io.NopCloser(bytes.NewReader(body))
+		}
+	}
+
+	if jarURL != nil {
+		c.storeCookies(jarURL, out)
+	}
+
+	// Prefer the declared length and flag bodies that arrived shorter than it.
+	out.ContentLength = int64(len(r.GetBody()))
+	if declared, ok := declaredLength(out.Header); ok {
+		out.ContentLength = declared
+		if expectsBody(req.GetMethod(), httpCode) && int64(len(r.GetBody())) < declared {
+			return out, fmt.Errorf("%w: declared %d bytes, received %d", ErrBodyTruncated, declared, len(r.GetBody()))
+		}
 	}
 
 	return out, nil
 }
 
+// declaredLength returns the Content-Length header value when it is a valid,
+// non-negative integer.
+func declaredLength(header http.Header) (int64, bool) {
+	value := header.Get("Content-Length")
+	if value == "" {
+		return 0, false
+	}
+
+	n, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+	if err != nil || n < 0 {
+		return 0, false
+	}
+
+	return n, true
+}
+
+// expectsBody reports whether a response to method with code carries a body
+// whose length should match Content-Length.
+func expectsBody(method string, code int) bool {
+	if method == http.MethodHead {
+		return false
+	}
+
+	return code >= 200 && code != http.StatusNoContent && code != http.StatusNotModified
+}
+
+// addRequestID sets RequestIDHeader on req unless the caller already set it.
+func addRequestID(req *proto.HTTPClient, id string) {
+	for name := range req.Headers {
+		if http.CanonicalHeaderKey(name) == RequestIDHeader {
+			return
+		}
+	}
+
+	if req.Headers == nil {
+		req.Headers = make(map[string]*proto.Header)
+	}
+	req.Headers[RequestIDHeader] = &proto.Header{Values: []string{id}}
+}
+
+// addCookies sets the Cookie header on req from the configured jar and returns
+// the parsed request URL so response cookies can be stored against it.
+func (c *HTTPClient) addCookies(req *proto.HTTPClient) *url.URL {
+	u, err := url.Parse(req.GetUrl())
+	if err != nil {
+		return nil
+	}
+
+	cookies := c.cfg.CookieJar.Cookies(u)
+	if len(cookies) == 0 {
+		return u
+	}
+
+	// Merge with any caller-supplied Cookie header into a single header value.
+	var parts []string
+	if existing := req.Headers["Cookie"]; existing != nil {
+		parts = append(parts, existing.GetValues()...)
+	}
+	for _, cookie := range cookies {
+		parts = append(parts, cookie.String())
+	}
+
+	if req.Headers == nil {
+		req.Headers = make(map[string]*proto.Header)
+	}
+	req.Headers["Cookie"] = &proto.Header{Values: []string{strings.Join(parts, "; ")}}
+
+	return u
+}
+
+// storeCookies saves the Set-Cookie headers of resp in the configured jar.
+func (c *HTTPClient) storeCookies(u *url.URL, resp *Response) {
+	var cookies []*http.Cookie
+	for _, line := range resp.Header.Values("Set-Cookie") {
+		cookie, err := http.ParseSetCookie(line)
+		if err != nil {
+			continue
+		}
+		cookies = append(cookies, cookie)
+	}
+
+	if len(cookies) > 0 {
+		c.cfg.CookieJar.SetCookies(u, cookies)
+	}
+}
+
+// call issues the httpclient host call bounded by the configured timeout.
+func (c *HTTPClient) call(payload []byte) ([]byte, error) {
+	return c.cfg.SDKConfig.Call(c.hostCall, c.cfg.Timeout, c.cfg.Capability, "call", payload)
+}
+
+// Close releases resources associated with the client. It is a no-op.
+func (c *HTTPClient) Close() error {
+	return nil
+}
+
+// Ping issues a lightweight health check call to the httpclient capability
+// without making an HTTP request. It returns nil when the host reports
+// StatusOK and the mapped status error otherwise.
+func (c *HTTPClient) Ping() error {
+	resp, err := c.cfg.SDKConfig.Call(c.hostCall, c.cfg.Timeout, c.cfg.Capability, sdk.PingFunction, nil)
+	if err != nil {
+		return sdk.HostCallError(err)
+	}
+
+	var status sdkproto.Status
+	if unmarshalErr := status.UnmarshalVT(resp); unmarshalErr != nil {
+		return errors.Join(ErrUnmarshalResponse, unmarshalErr)
+	}
+
+	return sdk.StatusToError(status.GetCode(), status.GetStatus())
+}
+
 // Response represents an HTTP response returned by the host.
 type Response struct {
 	// Status is the HTTP status text (e.g., "OK").
 	Status string
 	// StatusCode is the numeric HTTP status code (e.g., 200).
 	StatusCode int
-	// Header contains response headers. Nil is treated as empty.
+	// Header contains response headers keyed by canonical name. Header.Get
+	// returns the first value; use Header.Values for multi-value headers such
+	// as Set-Cookie.
 	Header http.Header
 	// Body is the response payload stream. It may be nil for empty bodies.
 	Body io.ReadCloser
+	// ContentLength is the declared Content-Length when the host sent a valid
+	// one, and the length of the received body otherwise.
+	ContentLength int64
+}
+
+// DecodeJSON reads the response body, decodes it as JSON into out, and closes
+// the body. It returns ErrNoBody when the response has no body and wraps decode
+// failures with ErrDecodeJSON.
+func (r *Response) DecodeJSON(out any) error {
+	if r == nil || r.Body == nil {
+		return ErrNoBody
+	}
+	defer func() { _ = r.Body.Close() }()
+
+	if err := json.NewDecoder(r.Body).Decode(out); err != nil {
+		return errors.Join(ErrDecodeJSON, err)
+	}
+
+	return nil
+}
+
+// JSONLines returns an iterator over the newline-delimited JSON values in the
+// response body, reading one line at a time so records can be processed
+// without buffering the whole body. Each value is yielded as raw JSON for the
+// caller to unmarshal.
+//
+// Blank lines are skipped, and a final line without a trailing newline is
+// still yielded. A line that is not valid JSON is yielded as an error wrapping
+// ErrDecodeJSON and iteration continues; a read failure is yielded and ends
+// iteration. The body is closed when iteration finishes or the caller stops
+// early. A response without a body yields ErrNoBody.
+func (r *Response) JSONLines() iter.Seq2[json.RawMessage, error] {
+	return func(yield func(json.RawMessage, error) bool) {
+		if r == nil || r.Body == nil {
+			yield(nil, ErrNoBody)
+			return
+		}
+		defer func() { _ = r.Body.Close() }()
+
+		reader := bufio.NewReader(r.Body)
+		for line := 1; ; line++ {
+			raw, readErr := reader.ReadBytes('\n')
+			if readErr != nil && !errors.Is(readErr, io.EOF) {
+				yield(nil, readErr)
+				return
+			}
+
+			if raw = bytes.TrimSpace(raw); len(raw) > 0 {
+				var err error
+				if !json.Valid(raw) {
+					raw, err = nil, fmt.Errorf("%w: line %d", ErrDecodeJSON, line)
+				}
+				if !yield(raw, err) {
+					return
+				}
+			}
+
+			if readErr != nil {
+				return
+			}
+		}
+	}
+}
+
+// NewResponse builds a Response the way the client does from a host reply:
+// Status is derived from code, header names are canonicalized, and a non-empty
+// body is exposed as a ReadCloser. It lets tests and fakes construct responses
+// that match real ones.
+func NewResponse(code int, body []byte, header http.Header) *Response {
+	out := &Response{
+		Status:        http.StatusText(code),
+		StatusCode:    code,
+		Header:        make(http.Header, len(header)),
+		ContentLength: int64(len(body)),
+	}
+
+	for name, values := range header {
+		key := http.CanonicalHeaderKey(name)
+		out.Header[key] = append(out.Header[key], values...)
+	}
+
+	if len(body) > 0 {
+		out.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	return out
+}
+
+// JSONResponse marshals v and returns it as a NewResponse body with an
+// application/json Content-Type. Marshal failures are wrapped with
+// ErrEncodeJSON.
+func JSONResponse(code int, v any) (*Response, error) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return nil, errors.Join(ErrEncodeJSON, err)
+	}
+
+	return NewResponse(code, body, http.Header{"Content-Type": {"application/json"}}), nil
 }
 
 // Request represents an HTTP request to be sent by the client.
@@ -146,8 +451,9 @@ var (
 	// ErrInvalidURL indicates a malformed or unsupported URL.
 	ErrInvalidURL = errors.New("invalid URL provided")
 
-	// ErrMarshalRequest wraps failures while encoding the request payload.
-	ErrMarshalRequest = errors.New("failed to create request")
+	// ErrMarshalRequest wraps failures while encoding the request payload. It is
+	// sdk.ErrMarshalRequest, shared by every capability client.
+	ErrMarshalRequest = sdk.ErrMarshalRequest
 
 	// ErrReadBody wraps failures while reading a request body stream.
 	ErrReadBody = errors.New("failed to read request body")
@@ -160,14 +466,25 @@ var (
 
 	// ErrNilRequest indicates Do received a nil Request pointer.
 	ErrNilRequest = errors.New("request is nil")
-)
 
-const (
-	hostStatusOK       = int32(200)
-	hostStatusPartial  = int32(206)
-	hostStatusBadInput = int32(400)
-	hostStatusMissing  = int32(404)
-	hostStatusError    = int32(500)
+	// ErrBodyTruncated indicates a response body shorter than its declared
+	// Content-Length. The partial response is returned alongside the error.
+	ErrBodyTruncated = errors.New("response body truncated")
+
+	// ErrInvalidBaseURL indicates a Config.BaseURL that is not an absolute http or https URL.
+	ErrInvalidBaseURL = errors.New("base URL must be an absolute http or https URL")
+
+	// ErrNoBody indicates a response without a body was asked to decode one.
+	ErrNoBody = errors.New("response has no body")
+
+	// ErrDecodeJSON wraps failures while decoding a response body as JSON.
+	ErrDecodeJSON = errors.New("failed to decode response body as JSON")
+
+	// ErrEncodeJSON wraps failures while encoding a JSONResponse body.
+	ErrEncodeJSON = errors.New("failed to encode response body as JSON")
+
+	// ErrInvalidPathSegment indicates an empty, "." or ".." segment passed to JoinPath.
+	ErrInvalidPathSegment = errors.New("invalid path segment")
 )
 
 // New creates a new HTTP client with the provided configuration.
@@ -179,21 +496,76 @@ func New(config Config) (*HTTPClient, error) {
 		hc.cfg.SDKConfig.Namespace = sdk.DefaultNamespace
 	}
 
+	// Per-client timeouts take precedence over the runtime default
+	if hc.cfg.Timeout == 0 {
+		hc.cfg.Timeout = hc.cfg.SDKConfig.DefaultTimeout
+	}
+
+	// Set default capability name if not provided
+	if hc.cfg.Capability == "" {
+		hc.cfg.Capability = capabilityName
+	}
+
 	// Set HostCall function if provided
 	hc.hostCall = wapc.HostCall
 	if config.HostCall != nil {
 		hc.hostCall = config.HostCall
 	}
 
+	// Parse the base URL once; it must be absolute to resolve against
+	if config.BaseURL != "" {
+		base, err := url.Parse(config.BaseURL)
+		if err != nil || !base.IsAbs() || !isSupportedTarget(base) {
+			return nil, ErrInvalidBaseURL
+		}
+		hc.baseURL = base
+	}
+
 	return hc, nil
 }
 
+// resolveURL resolves a relative urlStr against the configured base URL and
+// validates that the result has a host. Absolute URLs are returned unchanged.
+func (c *HTTPClient) resolveURL(urlStr string) (string, error) {
+	u, err := url.Parse(urlStr)
+	if err != nil || u == nil {
+		return "", ErrInvalidURL
+	}
+
+	if c.baseURL != nil && !u.IsAbs() {
+		u = c.baseURL.ResolveReference(u)
+		urlStr = u.String()
+	}
+
+	if !isSupportedTarget(u) {
+		return "", ErrInvalidURL
+	}
+
+	return urlStr, nil
+}
+
+// isSupportedTarget reports whether u names a host over a scheme the host
+// capability can fetch: http, https, or none, which is passed through as-is.
+// Other schemes such as ftp, file, or data are rejected before the host call.
+func isSupportedTarget(u *url.URL) bool {
+	if u == nil || u.Host == "" {
+		return false
+	}
+
+	switch strings.ToLower(u.Scheme) {
+	case "", "http", "https":
+		return true
+	default:
+		return false
+	}
+}
+
 // Get issues a GET to the specified URL and returns the response.
 func (c *HTTPClient) Get(urlStr string) (*Response, error) {
-	// Validate the URL
-	u, err := url.Parse(urlStr)
-	if err != nil || u == nil || u.Host == "" {
-		return &Response{}, ErrInvalidURL
+	// Resolve and validate the URL
+	urlStr, err := c.resolveURL(urlStr)
+	if err != nil {
+		return &Response{}, err
 	}
 
 	// Create the Protobuf request
@@ -201,17 +573,16 @@ func (c *HTTPClient) Get(urlStr string) (*Response, error) {
 		Method:   "GET",
 		Url:      urlStr,
 		Insecure: c.cfg.InsecureSkipVerify,
-		Headers:  make(map[string]*proto.Header),
 	}
 	return c.doHTTPCall(req)
 }
 
 // Post issues a POST to the URL with the provided contentType and body.
 func (c *HTTPClient) Post(urlStr, contentType string, body io.Reader) (*Response, error) {
-	// Validate the URL
-	u, err := url.Parse(urlStr)
-	if err != nil || u == nil || u.Host == "" {
-		return &Response{}, ErrInvalidURL
+	// Resolve and validate the URL
+	urlStr, err := c.resolveURL(urlStr)
+	if err != nil {
+		return &Response{}, err
 	}
 
 	// Read the body content if present
@@ -223,27 +594,15 @@ func (c *HTTPClient) Post(urlStr, contentType string, body io.Reader) (*Response
 		}
 	}
 
-	// Create the Protobuf request
-	headers := make(map[string]*proto.Header)
-	if contentType != "" {
-		headers["Content-Type"] = &proto.Header{Values: []string{contentType}}
-	}
-	req := &proto.HTTPClient{
-		Method:   "POST",
-		Url:      urlStr,
-		Insecure: c.cfg.InsecureSkipVerify,
-		Body:     bodyBytes,
-		Headers:  headers,
-	}
-	return c.doHTTPCall(req)
+	return c.doWithBody("POST", urlStr, contentType, bodyBytes)
 }
 
 // Put issues a PUT to the URL with the provided contentType and body.
 func (c *HTTPClient) Put(urlStr, contentType string, body io.Reader) (*Response, error) {
-	// Validate the URL
-	u, err := url.Parse(urlStr)
-	if err != nil || u == nil || u.Host == "" {
-		return &Response{}, ErrInvalidURL
+	// Resolve and validate the URL
+	urlStr, err := c.resolveURL(urlStr)
+	if err != nil {
+		return &Response{}, err
 	}
 
 	// Read the body content if present
@@ -255,27 +614,57 @@ func (c *HTTPClient) Put(urlStr, contentType string, body io.Reader) (*Response,
 		}
 	}
 
-	// Create the Protobuf request
-	headers := make(map[string]*proto.Header)
-	if contentType != "" {
-		headers["Content-Type"] = &proto.Header{Values: []string{contentType}}
+	return c.doWithBody("PUT", urlStr, contentType, bodyBytes)
+}
+
+// PostBytes issues a POST to the URL with the provided contentType and body.
+// Unlike Post, the body is used as-is without reading it through an
+// io.Reader; it must not be modified until PostBytes returns.
+func (c *HTTPClient) PostBytes(urlStr, contentType string, body []byte) (*Response, error) {
+	// Resolve and validate the URL
+	urlStr, err := c.resolveURL(urlStr)
+	if err != nil {
+		return &Response{}, err
 	}
+
+	return c.doWithBody("POST", urlStr, contentType, body)
+}
+
+// PutBytes issues a PUT to the URL with the provided contentType and body.
+// Unlike Put, the body is used as-is without reading it through an io.Reader;
+// it must not be modified until PutBytes returns.
+func (c *HTTPClient) PutBytes(urlStr, contentType string, body []byte) (*Response, error) {
+	// Resolve and validate the URL
+	urlStr, err := c.resolveURL(urlStr)
+	if err != nil {
+		return &Response{}, err
+	}
+
+	return c.doWithBody("PUT", urlStr, contentType, body)
+}
+
+// doWithBody sends a request with body and an optional Content-Type header.
+// The URL must already be validated.
+func (c *HTTPClient) doWithBody(method, urlStr, contentType string, body []byte) (*Response, error) {
+	// Create the Protobuf request; the headers map is only allocated when needed
 	req := &proto.HTTPClient{
-		Method:   "PUT",
+		Method:   method,
 		Url:      urlStr,
 		Insecure: c.cfg.InsecureSkipVerify,
-		Body:     bodyBytes,
-		Headers:  headers,
+		Body:     body,
+	}
+	if contentType != "" {
+		req.Headers = map[string]*proto.Header{"Content-Type": {Values: []string{contentType}}}
 	}
 	return c.doHTTPCall(req)
 }
 
 // Delete issues a DELETE to the specified URL.
 func (c *HTTPClient) Delete(urlStr string) (*Response, error) {
-	// Validate the URL
-	u, err := url.Parse(urlStr)
-	if err != nil || u == nil || u.Host == "" {
-		return &Response{}, ErrInvalidURL
+	// Resolve and validate the URL
+	urlStr, err := c.resolveURL(urlStr)
+	if err != nil {
+		return &Response{}, err
 	}
 
 	// Create the Protobuf request
@@ -283,19 +672,51 @@ func (c *HTTPClient) Delete(urlStr string) (*Response, error) {
 		Method:   "DELETE",
 		Url:      urlStr,
 		Insecure: c.cfg.InsecureSkipVerify,
-		Headers:  make(map[string]*proto.Header),
 	}
 	return c.doHTTPCall(req)
 }
 
+// Stat issues a HEAD to the specified URL and returns the response headers and
+// status code, such as to check whether a resource exists or read its
+// Content-Length. Any body the host erroneously includes is discarded.
+func (c *HTTPClient) Stat(urlStr string) (http.Header, int, error) {
+	// Resolve and validate the URL
+	urlStr, err := c.resolveURL(urlStr)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// Create the Protobuf request
+	req := &proto.HTTPClient{
+		Method:   http.MethodHead,
+		Url:      urlStr,
+		Insecure: c.cfg.InsecureSkipVerify,
+	}
+
+	resp, err := c.doHTTPCall(req)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if resp.Body != nil {
+		_ = resp.Body.Close()
+	}
+
+	return resp.Header, resp.StatusCode, nil
+}
+
 // Do issues a custom request built with NewRequest and returns the response.
 func (c *HTTPClient) Do(req *Request) (*Response, error) {
 	if req == nil {
 		return &Response{}, ErrNilRequest
 	}
 
-	// Validate the URL before touching the body stream.
-	if req.URL == nil || req.URL.Host == "" {
+	// Resolve relative URLs against the base, then validate before touching the body stream.
+	target := req.URL
+	if target != nil && c.baseURL != nil && !target.IsAbs() {
+		target = c.baseURL.ResolveReference(target)
+	}
+	if !isSupportedTarget(target) {
 		return &Response{}, ErrInvalidURL
 	}
 
@@ -313,13 +734,15 @@ func (c *HTTPClient) Do(req *Request) (*Response, error) {
 	// Create the Protobuf request
 	pbReq := &proto.HTTPClient{
 		Method:   req.Method,
-		Url:      req.URL.String(),
+		Url:      target.String(),
 		Insecure: c.cfg.InsecureSkipVerify,
 		Body:     bodyBytes,
-		Headers:  make(map[string]*proto.Header),
 	}
 
-	// Convert headers
+	// Convert headers, leaving the map nil when there are none
+	if len(req.Header) > 0 {
+		pbReq.Headers = make(map[string]*proto.Header, len(req.Header))
+	}
 	for key, values := range req.Header {
 		pbReq.Headers[key] = &proto.Header{
 			Values: values,
@@ -341,7 +764,7 @@ func NewRequest(method, urlString string, body io.Reader) (*Request, error) {
 
 	// Validate the URL
 	parsedURL, err := url.Parse(urlString)
-	if err != nil || parsedURL == nil || parsedURL.Host == "" {
+	if err != nil || !isSupportedTarget(parsedURL) {
 		return nil, ErrInvalidURL
 	}
 
@@ -360,6 +783,42 @@ func NewRequest(method, urlString string, body io.Reader) (*Request, error) {
 	return req, nil
 }
 
+// JoinPath percent-encodes each segment and appends it to the path of base.
+//
+// Unlike url.JoinPath, every segment is escaped as a single path element, so a
+// segment containing "/" or other reserved characters cannot introduce extra
+// path elements. The base must be an absolute URL with a host, and its query
+// and fragment are preserved. Empty, "." and ".." segments are rejected with
+// ErrInvalidPathSegment.
+func JoinPath(base string, segments ...string) (string, error) {
+	// Validate the base URL
+	u, err := url.Parse(base)
+	if err != nil || u == nil || u.Host == "" {
+		return "", ErrInvalidURL
+	}
+
+	var b strings.Builder
+	b.WriteString(strings.TrimSuffix(u.EscapedPath(), "/"))
+	for _, segment := range segments {
+		if segment == "" || segment == "." || segment == ".." {
+			return "", fmt.Errorf("%w: %q", ErrInvalidPathSegment, segment)
+		}
+		b.WriteByte('/')
+		b.WriteString(url.PathEscape(segment))
+	}
+
+	// Keep the escaped form in RawPath so encoded slashes survive String.
+	rawPath := b.String()
+	path, err := url.PathUnescape(rawPath)
+	if err != nil {
+		return "", ErrInvalidURL
+	}
+	u.Path = path
+	u.RawPath = rawPath
+
+	return u.String(), nil
+}
+
 func isValidMethod(method string) bool {
 	switch method {
 	case http.MethodGet,
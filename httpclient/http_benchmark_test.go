@@ -90,6 +90,84 @@ func BenchmarkHTTPClient(b *testing.B) {
 		}
 	})
 
+	// Bodies: compare reader-based and byte-slice request bodies.
+	b.Run("Bodies", func(b *testing.B) {
+		small := []byte(`{"data":"test"}`)
+		large := bytes.Repeat([]byte("a"), 64*1024) // ~64KiB
+
+		tt := []struct {
+			name    string
+			payload []byte
+			bytes   bool
+		}{
+			{"POST/reader/small", small, false},
+			{"POST/bytes/small", small, true},
+			{"POST/reader/large", large, false},
+			{"POST/bytes/large", large, true},
+		}
+
+		for _, tc := range tt {
+			b.Run(tc.name, func(b *testing.B) {
+				b.ReportAllocs()
+				b.ResetTimer()
+				for range b.N {
+					var (
+						r     *Response
+						opErr error
+					)
+					if tc.bytes {
+						r, opErr = c.PostBytes("http://example.com", "application/json", tc.payload)
+					} else {
+						r, opErr = c.Post("http://example.com", "application/json", bytes.NewReader(tc.payload))
+					}
+					if opErr != nil {
+						b.Fatalf("%s failed: %v", tc.name, opErr)
+					}
+					if r.Body != nil {
+						io.Copy(io.Discard, r.Body)
+						r.Body.Close()
+					}
+				}
+			})
+		}
+	})
+
+	// ResponseBodies: compare fresh and pooled response body buffers.
+	b.Run("ResponseBodies", func(b *testing.B) {
+		body := bytes.Repeat([]byte("r"), 32*1024) // ~32KiB
+		respBytes, _ := (&proto.HTTPClientResponse{
+			Status: &sdkproto.Status{Status: "OK", Code: 200},
+			Code:   200,
+			Body:   body,
+		}).MarshalVT()
+
+		for _, pooled := range []bool{false, true} {
+			name := "fresh"
+			if pooled {
+				name = "pooled"
+			}
+
+			b.Run(name, func(b *testing.B) {
+				hostCall := func(string, string, string, []byte) ([]byte, error) { return respBytes, nil }
+				pc, err := New(Config{HostCall: hostCall, PoolResponseBodies: pooled})
+				if err != nil {
+					b.Fatalf("client: %v", err)
+				}
+
+				b.ReportAllocs()
+				b.ResetTimer()
+				for range b.N {
+					r, opErr := pc.Get("http://example.com")
+					if opErr != nil {
+						b.Fatalf("%s failed: %v", name, opErr)
+					}
+					io.Copy(io.Discard, r.Body)
+					r.Body.Close()
+				}
+			})
+		}
+	})
+
 	// Do: exercise multiple verbs with varying payload sizes.
 	b.Run("Do", func(b *testing.B) {
 		b.ReportAllocs()
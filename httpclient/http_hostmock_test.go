@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"slices"
 	"strings"
 	"testing"
 
@@ -13,21 +14,15 @@ import (
 	proto "github.com/tarmac-project/protobuf-go/sdk/http"
 	sdk "github.com/tarmac-project/sdk"
 	"github.com/tarmac-project/sdk/hostmock"
+	"github.com/tarmac-project/sdk/httpclient/httpclienttest"
+	"github.com/tarmac-project/sdk/sdktest"
 )
 
 // Common canned responses used by hostmock tests.
 // okResponse returns a standard 200 OK response with JSON body.
 func okResponse() []byte {
-	resp := &proto.HTTPClientResponse{
-		Status: &sdkproto.Status{Status: "Host OK", Code: 200},
-		Code:   200,
-		Headers: map[string]*proto.Header{
-			"Content-Type": {Values: []string{"application/json"}},
-		},
-		Body: []byte(`{"message":"success"}`),
-	}
-	b, _ := resp.MarshalVT()
-	return b
+	header := http.Header{"Content-Type": {"application/json"}}
+	return httpclienttest.Response(sdktest.OK(), http.StatusOK, header, []byte(`{"message":"success"}`))
 }
 
 // Helpers
@@ -209,6 +204,191 @@ func TestHTTPClientHostMock_HappyPaths(t *testing.T) {
 	}
 }
 
+func TestHTTPClientHostMock_BytesBodies(t *testing.T) {
+	// PostBytes and PutBytes must deliver the body to the protobuf unchanged.
+	binary := []byte{0x00, 0xff, '\n', 'a', 0x7f}
+
+	tt := []struct {
+		name        string
+		method      string
+		url         string
+		contentType string
+		body        []byte
+		expectErr   error
+	}{
+		{"PostBytes JSON", http.MethodPost, "http://example.com/api", "application/json", []byte(`{"a":1}`), nil},
+		{"PostBytes binary", http.MethodPost, "http://example.com/api", "application/octet-stream", binary, nil},
+		{"PostBytes nil body", http.MethodPost, "http://example.com/api", "", nil, nil},
+		{"PutBytes binary", http.MethodPut, "http://example.com/api/1", "application/octet-stream", binary, nil},
+		{"PutBytes empty body", http.MethodPut, "http://example.com/api/1", "text/plain", []byte{}, nil},
+		{"PostBytes bad URL", http.MethodPost, "://bad-url", "", binary, ErrInvalidURL},
+		{"PutBytes bad URL", http.MethodPut, "", "", binary, ErrInvalidURL},
+		{"PostBytes ftp URL", http.MethodPost, "ftp://example.com/upload", "", binary, ErrInvalidURL},
+		{"PutBytes data URL", http.MethodPut, "data:text/plain,hello", "", binary, ErrInvalidURL},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			client, err := newClientWith(hostmock.Config{
+				ExpectedNamespace:  sdk.DefaultNamespace,
+				ExpectedCapability: "httpclient",
+				ExpectedFunction:   "call",
+				PayloadValidator: func(p []byte) error {
+					if err := baselineValidator(tc.method, tc.url, tc.body)(p); err != nil {
+						return err
+					}
+					var req proto.HTTPClient
+					if err := req.UnmarshalVT(p); err != nil {
+						return err
+					}
+					got := req.GetHeaders()["Content-Type"].GetValues()
+					if tc.contentType == "" && len(got) != 0 {
+						return fmt.Errorf("unexpected Content-Type %q", got)
+					}
+					if tc.contentType != "" && (len(got) != 1 || got[0] != tc.contentType) {
+						return fmt.Errorf("Content-Type mismatch: expected %q, got %q", tc.contentType, got)
+					}
+					return nil
+				},
+				Response: okResponse,
+			})
+			if err != nil {
+				t.Fatalf("client: %v", err)
+			}
+
+			var resp *Response
+			if tc.method == http.MethodPost {
+				resp, err = client.PostBytes(tc.url, tc.contentType, tc.body)
+			} else {
+				resp, err = client.PutBytes(tc.url, tc.contentType, tc.body)
+			}
+			if !errors.Is(err, tc.expectErr) {
+				t.Fatalf("expected error %v, got %v", tc.expectErr, err)
+			}
+			if tc.expectErr == nil && resp.StatusCode != http.StatusOK {
+				t.Fatalf("code: want %d got %d", http.StatusOK, resp.StatusCode)
+			}
+		})
+	}
+}
+
+func TestHTTPClientHostMock_NoHeaders(t *testing.T) {
+	// Requests without headers must still encode and succeed with no header entries.
+	noHeaders := func(p []byte) error {
+		var req proto.HTTPClient
+		if err := req.UnmarshalVT(p); err != nil {
+			return err
+		}
+		if len(req.GetHeaders()) != 0 {
+			return fmt.Errorf("expected no headers, got %v", req.GetHeaders())
+		}
+		return nil
+	}
+
+	client, err := newClientWith(hostmock.Config{
+		ExpectedNamespace:  sdk.DefaultNamespace,
+		ExpectedCapability: "httpclient",
+		ExpectedFunction:   "call",
+		PayloadValidator:   noHeaders,
+		Response:           okResponse,
+	})
+	if err != nil {
+		t.Fatalf("client: %v", err)
+	}
+
+	tt := []struct {
+		name string
+		call func() (*Response, error)
+	}{
+		{"GET", func() (*Response, error) { return client.Get("http://example.com") }},
+		{"DELETE", func() (*Response, error) { return client.Delete("http://example.com/1") }},
+		{"POST without content type", func() (*Response, error) {
+			return client.Post("http://example.com", "", strings.NewReader("body"))
+		}},
+		{"PutBytes without content type", func() (*Response, error) {
+			return client.PutBytes("http://example.com/1", "", []byte("body"))
+		}},
+		{"Do without headers", func() (*Response, error) {
+			req, err := NewRequest(http.MethodOptions, "http://example.com", nil)
+			if err != nil {
+				return nil, err
+			}
+			return client.Do(req)
+		}},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, err := tc.call()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("code: want %d got %d", http.StatusOK, resp.StatusCode)
+			}
+		})
+	}
+}
+
+func TestHTTPClientHostMock_ContentLength(t *testing.T) {
+	// Content-Length is surfaced on Response and checked against the received body.
+	tt := []struct {
+		name       string
+		method     string
+		code       int32
+		declared   string
+		body       []byte
+		wantLength int64
+		wantErr    error
+	}{
+		{"matching length", http.MethodGet, 200, "5", []byte("hello"), 5, nil},
+		{"no header uses body length", http.MethodGet, 200, "", []byte("hello"), 5, nil},
+		{"no header and no body", http.MethodGet, 200, "", nil, 0, nil},
+		{"shorter than declared", http.MethodGet, 200, "10", []byte("hello"), 10, ErrBodyTruncated},
+		{"longer than declared", http.MethodGet, 200, "2", []byte("hello"), 2, nil},
+		{"invalid header ignored", http.MethodGet, 200, "abc", []byte("hello"), 5, nil},
+		{"HEAD has no body", http.MethodHead, 200, "10", nil, 10, nil},
+		{"not modified has no body", http.MethodGet, 304, "10", nil, 10, nil},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			client, err := newClientWith(hostmock.Config{
+				Response: func() []byte {
+					resp := &proto.HTTPClientResponse{
+						Status:  &sdkproto.Status{Status: "OK", Code: 200},
+						Code:    tc.code,
+						Headers: map[string]*proto.Header{},
+						Body:    tc.body,
+					}
+					if tc.declared != "" {
+						resp.Headers["Content-Length"] = &proto.Header{Values: []string{tc.declared}}
+					}
+					b, _ := resp.MarshalVT()
+					return b
+				},
+			})
+			if err != nil {
+				t.Fatalf("client: %v", err)
+			}
+
+			resp, err := exec(client, tc.method, "http://example.com", "", nil, nil)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("expected error %v, got %v", tc.wantErr, err)
+			}
+			if resp.ContentLength != tc.wantLength {
+				t.Fatalf("content length: want %d got %d", tc.wantLength, resp.ContentLength)
+			}
+			if len(tc.body) > 0 {
+				got, _ := io.ReadAll(resp.Body)
+				if !bytes.Equal(got, tc.body) {
+					t.Fatalf("body: want %q got %q", tc.body, got)
+				}
+			}
+		})
+	}
+}
+
 func TestHTTPClientHostMock_HostFailures(t *testing.T) {
 	// Simulate hostcall failures across verbs, including Do.
 	tt := []struct{ name, method, url, contentType, body string }{
@@ -508,3 +688,366 @@ func TestHTTPClientHostMock_NoBodyResponses(t *testing.T) {
 		}
 	})
 }
+
+func TestStat(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		name     string
+		url      string
+		response func() []byte
+		pool     bool
+		wantCode int
+		wantLen  string
+		wantErr  error
+	}{
+		{
+			name: "Existing resource",
+			url:  "http://example.com/file",
+			response: func() []byte {
+				return httpclienttest.Response(sdktest.OK(), http.StatusOK, http.Header{"Content-Length": {"1024"}}, nil)
+			},
+			wantCode: http.StatusOK,
+			wantLen:  "1024",
+		},
+		{
+			name:     "Missing resource",
+			url:      "http://example.com/missing",
+			response: func() []byte { return httpclienttest.Response(sdktest.OK(), http.StatusNotFound, nil, nil) },
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name: "Host includes a body",
+			url:  "http://example.com/file",
+			response: func() []byte {
+				return httpclienttest.Response(sdktest.OK(), http.StatusOK, http.Header{"Content-Length": {"4"}}, []byte("oops"))
+			},
+			wantCode: http.StatusOK,
+			wantLen:  "4",
+		},
+		{
+			name: "Host includes a pooled body",
+			url:  "http://example.com/file",
+			response: func() []byte {
+				return httpclienttest.Response(sdktest.OK(), http.StatusOK, nil, []byte("oops"))
+			},
+			pool:     true,
+			wantCode: http.StatusOK,
+		},
+		{
+			name: "Host status error",
+			url:  "http://example.com/file",
+			response: func() []byte {
+				return httpclienttest.Response(sdktest.Failed(sdk.StatusError, "unreachable"), 0, nil, nil)
+			},
+			wantErr: sdk.ErrHostError,
+		},
+		{
+			name:    "Invalid URL",
+			url:     "ftp://example.com/file",
+			wantErr: ErrInvalidURL,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			hostCall := hostmock.Unexpected(t)
+			if tc.response != nil {
+				mock, err := hostmock.New(hostmock.Config{
+					ExpectedFunction: "call",
+					Response:         tc.response,
+					PayloadValidator: baselineValidator(http.MethodHead, tc.url, nil),
+				})
+				if err != nil {
+					t.Fatalf("failed to create hostmock: %v", err)
+				}
+				hostCall = mock.HostCall
+			}
+
+			client, err := New(Config{HostCall: hostCall, PoolResponseBodies: tc.pool})
+			if err != nil {
+				t.Fatalf("failed to create client: %v", err)
+			}
+
+			header, code, err := client.Stat(tc.url)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("Stat returned error %v, want %v", err, tc.wantErr)
+			}
+			if code != tc.wantCode {
+				t.Fatalf("status code mismatch: want %d, got %d", tc.wantCode, code)
+			}
+			if got := header.Get("Content-Length"); got != tc.wantLen {
+				t.Fatalf("Content-Length mismatch: want %q, got %q", tc.wantLen, got)
+			}
+		})
+	}
+}
+
+func TestHostStatusCode(t *testing.T) {
+	t.Parallel()
+
+	for _, code := range []int32{400, 404, 500} {
+		mock, err := hostmock.New(hostmock.Config{
+			Response: func() []byte {
+				resp := &proto.HTTPClientResponse{Status: &sdkproto.Status{Status: "rejected", Code: code}}
+				b, _ := resp.MarshalVT()
+				return b
+			},
+		})
+		if err != nil {
+			t.Fatalf("failed to create hostmock: %v", err)
+		}
+
+		client, err := New(Config{HostCall: mock.HostCall})
+		if err != nil {
+			t.Fatalf("failed to create client: %v", err)
+		}
+
+		_, err = client.Get("http://example.com")
+		if !errors.Is(err, sdk.ErrHostError) {
+			t.Fatalf("expected %v, got %v", sdk.ErrHostError, err)
+		}
+
+		var statusErr *sdk.HostStatusError
+		if !errors.As(err, &statusErr) {
+			t.Fatalf("expected *sdk.HostStatusError, got %T", err)
+		}
+		if statusErr.Code != code || statusErr.Message != "rejected" {
+			t.Fatalf("expected code %d, got %d (%q)", code, statusErr.Code, statusErr.Message)
+		}
+	}
+}
+
+func TestPing(t *testing.T) {
+	t.Parallel()
+
+	status := func(code int32, message string) func() []byte {
+		return func() []byte {
+			b, _ := (&sdkproto.Status{Code: code, Status: message}).MarshalVT()
+			return b
+		}
+	}
+
+	tt := []struct {
+		name    string
+		cfg     hostmock.Config
+		wantErr error
+	}{
+		{
+			name:    "healthy",
+			cfg:     hostmock.Config{Response: status(200, "OK")},
+			wantErr: nil,
+		},
+		{
+			name:    "unhealthy status",
+			cfg:     hostmock.Config{Response: status(500, "unavailable")},
+			wantErr: sdk.ErrHostError,
+		},
+		{
+			name:    "host call failure",
+			cfg:     hostmock.Config{Fail: true, Error: errors.New("no such function")},
+			wantErr: sdk.ErrHostCall,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			tc.cfg.ExpectedCapability = "httpclient"
+			tc.cfg.ExpectedFunction = sdk.PingFunction
+			tc.cfg.PayloadValidator = func(payload []byte) error {
+				if len(payload) != 0 {
+					return fmt.Errorf("expected empty payload, got %q", payload)
+				}
+				return nil
+			}
+			mock, err := hostmock.New(tc.cfg)
+			if err != nil {
+				t.Fatalf("failed to create hostmock: %v", err)
+			}
+
+			client, err := New(Config{HostCall: mock.HostCall})
+			if err != nil {
+				t.Fatalf("New returned error: %v", err)
+			}
+
+			if err := client.Ping(); !errors.Is(err, tc.wantErr) {
+				t.Fatalf("expected %v, got %v", tc.wantErr, err)
+			}
+			if got := mock.Count(); got != 1 {
+				t.Fatalf("expected one host call, got %d", got)
+			}
+		})
+	}
+}
+
+func TestCapabilityOverride(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		name       string
+		capability string
+		want       string
+	}{
+		{name: "default", want: capabilityName},
+		{name: "custom", capability: "custom-httpclient", want: "custom-httpclient"},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			mock, err := hostmock.New(hostmock.Config{
+				ExpectedCapability: tc.want,
+				Response: func() []byte {
+					b, _ := (&sdkproto.Status{Code: 200}).MarshalVT()
+					return b
+				},
+			})
+			if err != nil {
+				t.Fatalf("failed to create hostmock: %v", err)
+			}
+
+			client, err := New(Config{HostCall: mock.HostCall, Capability: tc.capability})
+			if err != nil {
+				t.Fatalf("New returned error: %v", err)
+			}
+			if err := client.Ping(); err != nil {
+				t.Fatalf("Ping returned error: %v", err)
+			}
+
+			calls := mock.Calls()
+			if len(calls) != 1 || calls[0].Capability != tc.want {
+				t.Fatalf("expected one call to capability %q, got %+v", tc.want, calls)
+			}
+		})
+	}
+}
+
+func TestRequestID(t *testing.T) {
+	t.Parallel()
+
+	requestIDs := func(t *testing.T, payload []byte) []string {
+		t.Helper()
+		var req proto.HTTPClient
+		if err := req.UnmarshalVT(payload); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+		var ids []string
+		for name, header := range req.GetHeaders() {
+			if http.CanonicalHeaderKey(name) == RequestIDHeader {
+				ids = append(ids, header.GetValues()...)
+			}
+		}
+		return ids
+	}
+
+	tt := []struct {
+		name      string
+		requestID string
+		header    string
+		want      []string
+	}{
+		{name: "propagated", requestID: "req-123", want: []string{"req-123"}},
+		{name: "caller header wins", requestID: "req-123", header: "caller-id", want: []string{"caller-id"}},
+		{name: "unset", want: nil},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			mock, err := hostmock.New(hostmock.Config{Response: okResponse})
+			if err != nil {
+				t.Fatalf("failed to create hostmock: %v", err)
+			}
+
+			client, err := New(Config{
+				SDKConfig: sdk.RuntimeConfig{RequestID: tc.requestID},
+				HostCall:  mock.HostCall,
+			})
+			if err != nil {
+				t.Fatalf("New returned error: %v", err)
+			}
+
+			req, err := NewRequest("GET", "http://example.com", nil)
+			if err != nil {
+				t.Fatalf("NewRequest returned error: %v", err)
+			}
+			if tc.header != "" {
+				req.Header.Set("x-request-id", tc.header)
+			}
+			if _, err := client.Do(req); err != nil {
+				t.Fatalf("Do returned error: %v", err)
+			}
+
+			payloads := mock.Payloads()
+			if len(payloads) != 1 {
+				t.Fatalf("expected 1 host call, got %d", len(payloads))
+			}
+			if got := requestIDs(t, payloads[0]); !slices.Equal(got, tc.want) {
+				t.Fatalf("request ID mismatch: want %q, got %q", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestClose(t *testing.T) {
+	t.Parallel()
+
+	mock, err := hostmock.New(hostmock.Config{})
+	if err != nil {
+		t.Fatalf("failed to create hostmock: %v", err)
+	}
+
+	var client Client
+	client, err = New(Config{HostCall: mock.HostCall})
+	if err != nil {
+		t.Fatalf("New returned error: %v", err)
+	}
+
+	// Close must be safe to defer unconditionally and to call more than once.
+	for i := range 2 {
+		if err := client.Close(); err != nil {
+			t.Fatalf("Close call %d returned error: %v", i+1, err)
+		}
+	}
+	if mock.Count() != 0 {
+		t.Fatalf("expected Close to make no host calls, got %d", mock.Count())
+	}
+}
+
+func TestEmptyHostResponse(t *testing.T) {
+	t.Parallel()
+
+	ops := []struct {
+		name string
+		call func(Client) error
+	}{
+		{name: "get", call: func(c Client) error { _, err := c.Get("http://example.com"); return err }},
+		{name: "stat", call: func(c Client) error { _, _, err := c.Stat("http://example.com"); return err }},
+		{name: "ping", call: func(c Client) error { return c.Ping() }},
+	}
+
+	payloads := map[string][]byte{"nil": nil, "empty": {}}
+
+	for _, op := range ops {
+		for kind, payload := range payloads {
+			t.Run(op.name+" "+kind, func(t *testing.T) {
+				t.Parallel()
+
+				client, err := newClientWith(hostmock.Config{Response: func() []byte { return payload }})
+				if err != nil {
+					t.Fatalf("failed to create client: %v", err)
+				}
+
+				if err := op.call(client); !errors.Is(err, sdk.ErrHostResponseInvalid) {
+					t.Fatalf("expected %v, got %v", sdk.ErrHostResponseInvalid, err)
+				}
+			})
+		}
+	}
+}
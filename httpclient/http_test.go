@@ -1,17 +1,24 @@
 package httpclient
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"slices"
 	"strings"
 	"testing"
 	"testing/iotest"
+	"time"
 
 	sdkproto "github.com/tarmac-project/protobuf-go/sdk"
 	proto "github.com/tarmac-project/protobuf-go/sdk/http"
 	sdk "github.com/tarmac-project/sdk"
 	"github.com/tarmac-project/sdk/hostmock"
+	"github.com/tarmac-project/sdk/sdktest"
 
 	"github.com/madflojo/testlazy/things/testurl"
 )
@@ -65,10 +72,14 @@ func TestHTTPClient(t *testing.T) {
 			{"GET success", "GET", "http://example.com", "", nil, nil},
 			{"GET with bad URL", "GET", "://bad-url", "", nil, ErrInvalidURL},
 			{"GET with empty URL", "GET", "", "", nil, ErrInvalidURL},
+			{"GET with ftp URL", "GET", "ftp://example.com/file", "", nil, ErrInvalidURL},
+			{"GET with data URL", "GET", "data:text/plain,hello", "", nil, ErrInvalidURL},
 			{"POST success", "POST", "http://example.com", "application/json", strings.NewReader(`{"x":"y"}`), nil},
 			{"POST no body", "POST", "http://example.com", "text/plain", nil, nil},
 			{"POST with bad URL", "POST", "://bad-url", "", nil, ErrInvalidURL},
 			{"POST with empty URL", "POST", "", "", nil, ErrInvalidURL},
+			{"POST with ftp URL", "POST", "ftp://example.com/file", "", nil, ErrInvalidURL},
+			{"POST with data URL", "POST", "data:text/plain,hello", "", nil, ErrInvalidURL},
 			{"POST with empty content type", "POST", "http://example.com", "", strings.NewReader("body"), nil},
 			{
 				"POST with bad reader",
@@ -81,6 +92,8 @@ func TestHTTPClient(t *testing.T) {
 			{"PUT success", "PUT", "http://example.com", "text/plain", strings.NewReader("body"), nil},
 			{"PUT with bad URL", "PUT", "://bad-url", "", nil, ErrInvalidURL},
 			{"PUT with empty URL", "PUT", "", "", nil, ErrInvalidURL},
+			{"PUT with ftp URL", "PUT", "ftp://example.com/file", "", nil, ErrInvalidURL},
+			{"PUT with data URL", "PUT", "data:text/plain,hello", "", nil, ErrInvalidURL},
 			{"PUT with empty content type", "PUT", "http://example.com", "", strings.NewReader("body"), nil},
 			{
 				"PUT with bad reader",
@@ -93,6 +106,8 @@ func TestHTTPClient(t *testing.T) {
 			{"DELETE success", "DELETE", "http://example.com", "", nil, nil},
 			{"DELETE with bad URL", "DELETE", "://bad-url", "", nil, ErrInvalidURL},
 			{"DELETE with empty URL", "DELETE", "", "", nil, ErrInvalidURL},
+			{"DELETE with ftp URL", "DELETE", "ftp://example.com/file", "", nil, ErrInvalidURL},
+			{"DELETE with data URL", "DELETE", "data:text/plain,hello", "", nil, ErrInvalidURL},
 		}
 
 		for _, tc := range tt {
@@ -148,6 +163,9 @@ func TestHTTPClient(t *testing.T) {
 			{"Valid PATCH request", "PATCH", "http://example.com", strings.NewReader(`{"flag":true}`), nil},
 			{"Bad URL", "PATCH", "://bad-url", nil, ErrInvalidURL},
 			{"Empty URL", "PATCH", "", nil, ErrInvalidURL},
+			{"FTP URL", "PATCH", "ftp://example.com/file", nil, ErrInvalidURL},
+			{"File URL", "PATCH", "file://localhost/etc/hosts", nil, ErrInvalidURL},
+			{"Data URL", "PATCH", "data:text/plain,hello", nil, ErrInvalidURL},
 			{"Empty Method", "", "http://example.com", nil, ErrInvalidMethod},
 			{"Invalid Method", "INVALID_METHOD", "http://example.com", nil, ErrInvalidMethod},
 			{"Nil Body", "PATCH", "http://example.com", nil, nil},
@@ -175,6 +193,16 @@ func TestHTTPClient(t *testing.T) {
 			{"Do with invalid host URL", &Request{Method: "GET", URL: testurl.URLInvalidHost()}, nil},
 			{"Do with no host URL", &Request{Method: "GET", URL: testurl.URLNoHost()}, ErrInvalidURL},
 			{"Do with empty URL", &Request{Method: "GET"}, ErrInvalidURL},
+			{
+				"Do with ftp URL",
+				&Request{Method: "GET", URL: testurl.MustParse("ftp://example.com/file")},
+				ErrInvalidURL,
+			},
+			{
+				"Do with data URL",
+				&Request{Method: "GET", URL: testurl.MustParse("data:text/plain,hello")},
+				ErrInvalidURL,
+			},
 			{
 				"Do POST with body",
 				&Request{
@@ -263,3 +291,707 @@ func TestHTTPClient(t *testing.T) {
 		}
 	})
 }
+
+func TestResponseHeaders(t *testing.T) {
+	t.Parallel()
+
+	mock, err := hostmock.New(hostmock.Config{
+		Response: func() []byte {
+			resp := &proto.HTTPClientResponse{
+				Status: &sdkproto.Status{Status: "OK", Code: 200},
+				Code:   200,
+				Headers: map[string]*proto.Header{
+					"Set-Cookie":   {Values: []string{"session=abc; Path=/", "theme=dark"}},
+					"content-type": {Values: []string{"text/plain"}},
+				},
+			}
+			b, _ := resp.MarshalVT()
+			return b
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to create hostmock: %v", err)
+	}
+
+	client, err := New(Config{HostCall: mock.HostCall})
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	resp, err := client.Get("http://example.com")
+	if err != nil {
+		t.Fatalf("Get returned error: %v", err)
+	}
+
+	wantCookies := []string{"session=abc; Path=/", "theme=dark"}
+	if got := resp.Header.Values("Set-Cookie"); !slices.Equal(got, wantCookies) {
+		t.Fatalf("Set-Cookie values mismatch: want %q, got %q", wantCookies, got)
+	}
+	if got := resp.Header.Get("Set-Cookie"); got != wantCookies[0] {
+		t.Fatalf("Set-Cookie first value mismatch: want %q, got %q", wantCookies[0], got)
+	}
+	if got := resp.Header.Get("Content-Type"); got != "text/plain" {
+		t.Fatalf("expected canonicalized Content-Type, got %q", got)
+	}
+}
+
+func TestResponseDecodeJSON(t *testing.T) {
+	t.Parallel()
+
+	type payload struct {
+		Message string `json:"message"`
+		Count   int    `json:"count"`
+	}
+
+	tt := []struct {
+		name    string
+		body    []byte
+		want    payload
+		wantErr error
+	}{
+		{name: "decodes struct", body: []byte(`{"message":"success","count":2}`), want: payload{"success", 2}},
+		{name: "empty body", body: nil, wantErr: ErrNoBody},
+		{name: "invalid JSON", body: []byte(`{"message":`), wantErr: ErrDecodeJSON},
+		{name: "type mismatch", body: []byte(`{"count":"two"}`), wantErr: ErrDecodeJSON},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			mock, err := hostmock.New(hostmock.Config{
+				Response: func() []byte {
+					resp := &proto.HTTPClientResponse{
+						Status: &sdkproto.Status{Status: "OK", Code: 200},
+						Code:   200,
+						Body:   tc.body,
+					}
+					b, _ := resp.MarshalVT()
+					return b
+				},
+			})
+			if err != nil {
+				t.Fatalf("failed to create hostmock: %v", err)
+			}
+
+			client, err := New(Config{HostCall: mock.HostCall})
+			if err != nil {
+				t.Fatalf("failed to create client: %v", err)
+			}
+
+			resp, err := client.Get("http://example.com")
+			if err != nil {
+				t.Fatalf("Get returned error: %v", err)
+			}
+
+			var got payload
+			if err := resp.DecodeJSON(&got); !errors.Is(err, tc.wantErr) {
+				t.Fatalf("expected error %v, got %v", tc.wantErr, err)
+			}
+			if tc.wantErr == nil && got != tc.want {
+				t.Fatalf("decoded value mismatch: want %+v, got %+v", tc.want, got)
+			}
+		})
+	}
+}
+
+// trackingBody is a response body that records whether it was closed.
+type trackingBody struct {
+	io.Reader
+	closed bool
+}
+
+func (b *trackingBody) Close() error {
+	b.closed = true
+	return nil
+}
+
+func TestResponseJSONLines(t *testing.T) {
+	t.Parallel()
+
+	type step struct {
+		raw     string
+		wantErr error
+	}
+
+	tt := []struct {
+		name  string
+		body  string
+		steps []step
+	}{
+		{
+			name:  "Multiple objects",
+			body:  "{\"id\":1}\n{\"id\":2}\n",
+			steps: []step{{raw: `{"id":1}`}, {raw: `{"id":2}`}},
+		},
+		{
+			name:  "Final line without newline",
+			body:  "{\"id\":1}\n{\"id\":2}",
+			steps: []step{{raw: `{"id":1}`}, {raw: `{"id":2}`}},
+		},
+		{
+			name:  "Blank lines and CRLF",
+			body:  "{\"id\":1}\r\n\r\n\n[2]\r\n",
+			steps: []step{{raw: `{"id":1}`}, {raw: `[2]`}},
+		},
+		{
+			name:  "Invalid line continues",
+			body:  "{\"id\":1}\nnot json\n{\"id\":3}\n",
+			steps: []step{{raw: `{"id":1}`}, {wantErr: ErrDecodeJSON}, {raw: `{"id":3}`}},
+		},
+		{
+			name:  "Truncated final line",
+			body:  "{\"id\":1}\n{\"id\":",
+			steps: []step{{raw: `{"id":1}`}, {wantErr: ErrDecodeJSON}},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			body := &trackingBody{Reader: strings.NewReader(tc.body)}
+			resp := &Response{StatusCode: http.StatusOK, Body: body}
+
+			var got []step
+			for raw, err := range resp.JSONLines() {
+				got = append(got, step{raw: string(raw), wantErr: err})
+			}
+
+			if len(got) != len(tc.steps) {
+				t.Fatalf("expected %d records, got %d: %+v", len(tc.steps), len(got), got)
+			}
+			for i, want := range tc.steps {
+				if !errors.Is(got[i].wantErr, want.wantErr) || got[i].raw != want.raw {
+					t.Fatalf("record %d: want %+v, got %+v", i, want, got[i])
+				}
+			}
+			if !body.closed {
+				t.Fatalf("expected body to be closed")
+			}
+		})
+	}
+
+	t.Run("Decodes records", func(t *testing.T) {
+		t.Parallel()
+
+		resp := NewResponse(http.StatusOK, []byte("{\"id\":1}\n{\"id\":2}\n"), nil)
+
+		var ids []int
+		for raw, err := range resp.JSONLines() {
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var record struct {
+				ID int `json:"id"`
+			}
+			if err := json.Unmarshal(raw, &record); err != nil {
+				t.Fatalf("failed to decode record: %v", err)
+			}
+			ids = append(ids, record.ID)
+		}
+
+		if !slices.Equal(ids, []int{1, 2}) {
+			t.Fatalf("expected ids [1 2], got %v", ids)
+		}
+	})
+
+	t.Run("Early stop closes body", func(t *testing.T) {
+		t.Parallel()
+
+		body := &trackingBody{Reader: strings.NewReader("{}\n{}\n{}\n")}
+		resp := &Response{Body: body}
+		for range resp.JSONLines() {
+			break
+		}
+
+		if !body.closed {
+			t.Fatalf("expected body to be closed after an early stop")
+		}
+	})
+
+	t.Run("Read error ends iteration", func(t *testing.T) {
+		t.Parallel()
+
+		readErr := errors.New("connection reset")
+		body := &trackingBody{Reader: io.MultiReader(strings.NewReader("{}\n"), iotest.ErrReader(readErr))}
+		resp := &Response{Body: body}
+
+		var errs []error
+		for _, err := range resp.JSONLines() {
+			errs = append(errs, err)
+		}
+
+		if len(errs) != 2 || errs[0] != nil || !errors.Is(errs[1], readErr) {
+			t.Fatalf("expected one record then %v, got %v", readErr, errs)
+		}
+	})
+
+	t.Run("No body", func(t *testing.T) {
+		t.Parallel()
+
+		var errs []error
+		for _, err := range (&Response{}).JSONLines() {
+			errs = append(errs, err)
+		}
+
+		if len(errs) != 1 || !errors.Is(errs[0], ErrNoBody) {
+			t.Fatalf("expected a single %v, got %v", ErrNoBody, errs)
+		}
+	})
+}
+
+func TestBaseURL(t *testing.T) {
+	t.Parallel()
+
+	t.Run("invalid base", func(t *testing.T) {
+		t.Parallel()
+
+		for _, base := range []string{"/api/v1", "example.com/api", "://bad", "ftp://example.com/pub"} {
+			if _, err := New(Config{BaseURL: base}); !errors.Is(err, ErrInvalidBaseURL) {
+				t.Fatalf("base %q: expected %v, got %v", base, ErrInvalidBaseURL, err)
+			}
+		}
+	})
+
+	tt := []struct {
+		name    string
+		base    string
+		call    func(c *HTTPClient, url string) (*Response, error)
+		url     string
+		wantURL string
+		wantErr error
+	}{
+		{
+			name:    "relative path",
+			base:    "https://api.example.com/v1/",
+			url:     "users/42",
+			wantURL: "https://api.example.com/v1/users/42",
+		},
+		{
+			name:    "root-relative path",
+			base:    "https://api.example.com/v1/",
+			url:     "/health",
+			wantURL: "https://api.example.com/health",
+		},
+		{
+			name:    "query only",
+			base:    "https://api.example.com/v1/users",
+			url:     "?page=2",
+			wantURL: "https://api.example.com/v1/users?page=2",
+		},
+		{
+			name:    "absolute URL bypasses base",
+			base:    "https://api.example.com/v1/",
+			url:     "http://other.example.com/x",
+			wantURL: "http://other.example.com/x",
+		},
+		{
+			name: "post resolves too",
+			base: "https://api.example.com/v1/",
+			call: func(c *HTTPClient, url string) (*Response, error) {
+				return c.PostBytes(url, "application/json", []byte(`{}`))
+			},
+			url:     "items",
+			wantURL: "https://api.example.com/v1/items",
+		},
+		{
+			name: "do resolves relative request URL",
+			base: "https://api.example.com/v1/",
+			call: func(c *HTTPClient, raw string) (*Response, error) {
+				u, err := url.Parse(raw)
+				if err != nil {
+					return nil, err
+				}
+				return c.Do(&Request{Method: http.MethodPatch, URL: u, Header: http.Header{}})
+			},
+			url:     "items/7",
+			wantURL: "https://api.example.com/v1/items/7",
+		},
+		{
+			name:    "relative without base",
+			url:     "users/42",
+			wantErr: ErrInvalidURL,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			mock, err := hostmock.New(hostmock.Config{
+				PayloadValidator: func(p []byte) error {
+					var req proto.HTTPClient
+					if err := req.UnmarshalVT(p); err != nil {
+						return err
+					}
+					if req.GetUrl() != tc.wantURL {
+						return fmt.Errorf("url mismatch: expected %s, got %s", tc.wantURL, req.GetUrl())
+					}
+					return nil
+				},
+				Response: func() []byte {
+					b, _ := (&proto.HTTPClientResponse{Status: &sdkproto.Status{Code: 200}, Code: 200}).MarshalVT()
+					return b
+				},
+			})
+			if err != nil {
+				t.Fatalf("failed to create hostmock: %v", err)
+			}
+
+			client, err := New(Config{HostCall: mock.HostCall, BaseURL: tc.base})
+			if err != nil {
+				t.Fatalf("failed to create client: %v", err)
+			}
+
+			call := tc.call
+			if call == nil {
+				call = func(c *HTTPClient, url string) (*Response, error) { return c.Get(url) }
+			}
+			if _, err := call(client, tc.url); !errors.Is(err, tc.wantErr) {
+				t.Fatalf("expected error %v, got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestWireRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	t.Run("HTTPClient", func(t *testing.T) {
+		t.Parallel()
+		sdktest.AssertRoundTrip(t, &proto.HTTPClient{
+			Method: http.MethodPost,
+			Url:    "https://example.com/api",
+			Headers: map[string]*proto.Header{
+				"Content-Type": {Values: []string{"application/json"}},
+				"Accept":       {Values: []string{"text/plain", "application/json"}},
+			},
+			Body:     []byte(`{"x":"y"}`),
+			Insecure: true,
+		})
+	})
+
+	t.Run("HTTPClientResponse", func(t *testing.T) {
+		t.Parallel()
+		sdktest.AssertRoundTrip(t, &proto.HTTPClientResponse{
+			Status: &sdkproto.Status{Status: "OK", Code: 200},
+			Code:   201,
+			Headers: map[string]*proto.Header{
+				"Set-Cookie": {Values: []string{"a=1", "b=2"}},
+			},
+			Body: []byte("created"),
+		})
+	})
+}
+
+func TestJoinPath(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		name     string
+		base     string
+		segments []string
+		want     string
+		wantErr  error
+	}{
+		{
+			name:     "simple segments",
+			base:     "https://example.com/api",
+			segments: []string{"users", "42"},
+			want:     "https://example.com/api/users/42",
+		},
+		{
+			name:     "trailing slash on base",
+			base:     "https://example.com/api/",
+			segments: []string{"users"},
+			want:     "https://example.com/api/users",
+		},
+		{
+			name:     "segment with slash",
+			base:     "https://example.com/files",
+			segments: []string{"a/b/c.txt"},
+			want:     "https://example.com/files/a%2Fb%2Fc.txt",
+		},
+		{
+			name:     "reserved characters",
+			base:     "https://example.com",
+			segments: []string{"a b?c#d%e"},
+			want:     "https://example.com/a%20b%3Fc%23d%25e",
+		},
+		{
+			name:     "already escaped base path preserved",
+			base:     "https://example.com/a%2Fb",
+			segments: []string{"c"},
+			want:     "https://example.com/a%2Fb/c",
+		},
+		{
+			name:     "query and fragment preserved",
+			base:     "https://example.com/api?v=1#top",
+			segments: []string{"items"},
+			want:     "https://example.com/api/items?v=1#top",
+		},
+		{
+			name: "no segments",
+			base: "https://example.com/api",
+			want: "https://example.com/api",
+		},
+		{
+			name:     "dot dot segment rejected",
+			base:     "https://example.com/api",
+			segments: []string{".."},
+			wantErr:  ErrInvalidPathSegment,
+		},
+		{
+			name:     "empty segment rejected",
+			base:     "https://example.com/api",
+			segments: []string{"users", ""},
+			wantErr:  ErrInvalidPathSegment,
+		},
+		{
+			name:     "base without host",
+			base:     "/relative/path",
+			segments: []string{"x"},
+			wantErr:  ErrInvalidURL,
+		},
+		{
+			name:     "malformed base",
+			base:     "://bad-url",
+			segments: []string{"x"},
+			wantErr:  ErrInvalidURL,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := JoinPath(tc.base, tc.segments...)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("unexpected error: want %v got %v", tc.wantErr, err)
+			}
+			if got != tc.want {
+				t.Fatalf("unexpected URL: want %q got %q", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	t.Parallel()
+
+	release := make(chan struct{})
+	t.Cleanup(func() { close(release) })
+
+	blocking := func(string, string, string, []byte) ([]byte, error) {
+		<-release
+		return nil, nil
+	}
+
+	s, err := sdk.New(sdk.Config{
+		Handler:        func(b []byte) ([]byte, error) { return b, nil },
+		DefaultTimeout: 10 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("sdk.New returned error: %v", err)
+	}
+
+	t.Run("inherits SDK default", func(t *testing.T) {
+		t.Parallel()
+
+		client, err := New(Config{SDKConfig: s.Config(), HostCall: blocking})
+		if err != nil {
+			t.Fatalf("failed to create client: %v", err)
+		}
+		_, err = client.Get("http://example.com")
+		if !errors.Is(err, context.DeadlineExceeded) || errors.Is(err, sdk.ErrHostCall) {
+			t.Fatalf("expected %v without %v, got %v", context.DeadlineExceeded, sdk.ErrHostCall, err)
+		}
+	})
+
+	t.Run("client override wins", func(t *testing.T) {
+		t.Parallel()
+
+		runtime := s.Config()
+		runtime.DefaultTimeout = time.Hour
+
+		client, err := New(Config{SDKConfig: runtime, HostCall: blocking, Timeout: 10 * time.Millisecond})
+		if err != nil {
+			t.Fatalf("failed to create client: %v", err)
+		}
+		if _, err := client.Post("http://example.com", "text/plain", nil); !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("expected %v, got %v", context.DeadlineExceeded, err)
+		}
+	})
+
+	t.Run("delayed host exceeds timeout", func(t *testing.T) {
+		t.Parallel()
+
+		mock, err := hostmock.New(hostmock.Config{Response: okResponse, Delay: 100 * time.Millisecond})
+		if err != nil {
+			t.Fatalf("failed to create hostmock: %v", err)
+		}
+		client, err := New(Config{SDKConfig: s.Config(), HostCall: mock.HostCall})
+		if err != nil {
+			t.Fatalf("failed to create client: %v", err)
+		}
+		if _, err := client.Get("http://example.com"); !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("expected %v, got %v", context.DeadlineExceeded, err)
+		}
+	})
+
+	t.Run("fast host within timeout", func(t *testing.T) {
+		t.Parallel()
+
+		mock, err := hostmock.New(hostmock.Config{Response: okResponse})
+		if err != nil {
+			t.Fatalf("failed to create hostmock: %v", err)
+		}
+		client, err := New(Config{SDKConfig: s.Config(), HostCall: mock.HostCall, Timeout: time.Minute})
+		if err != nil {
+			t.Fatalf("failed to create client: %v", err)
+		}
+		if _, err := client.Get("http://example.com"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+}
+
+type recordingLogger struct {
+	messages []string
+}
+
+func (l *recordingLogger) Debug(message string) { l.messages = append(l.messages, message) }
+
+func TestRuntimeConfigPropagation(t *testing.T) {
+	t.Parallel()
+
+	t.Run("runtime timeout becomes client timeout", func(t *testing.T) {
+		t.Parallel()
+
+		client, err := New(Config{SDKConfig: sdk.RuntimeConfig{DefaultTimeout: 3 * time.Second}})
+		if err != nil {
+			t.Fatalf("failed to create client: %v", err)
+		}
+		if client.cfg.Timeout != 3*time.Second {
+			t.Fatalf("expected timeout %v, got %v", 3*time.Second, client.cfg.Timeout)
+		}
+
+		client, err = New(Config{SDKConfig: sdk.RuntimeConfig{DefaultTimeout: 3 * time.Second}, Timeout: time.Second})
+		if err != nil {
+			t.Fatalf("failed to create client: %v", err)
+		}
+		if client.cfg.Timeout != time.Second {
+			t.Fatalf("expected timeout %v, got %v", time.Second, client.cfg.Timeout)
+		}
+	})
+
+	t.Run("runtime logger receives host call failures", func(t *testing.T) {
+		t.Parallel()
+
+		logger := &recordingLogger{}
+		client, err := New(Config{
+			SDKConfig: sdk.RuntimeConfig{Logger: logger},
+			HostCall: func(string, string, string, []byte) ([]byte, error) {
+				return nil, errors.New("host down")
+			},
+		})
+		if err != nil {
+			t.Fatalf("failed to create client: %v", err)
+		}
+
+		if _, err := client.Get("http://example.com"); !errors.Is(err, sdk.ErrHostCall) {
+			t.Fatalf("expected %v, got %v", sdk.ErrHostCall, err)
+		}
+		if len(logger.messages) != 1 || !strings.Contains(logger.messages[0], "host down") {
+			t.Fatalf("unexpected logged messages: %q", logger.messages)
+		}
+	})
+}
+
+func TestNewResponse(t *testing.T) {
+	t.Parallel()
+
+	t.Run("fields", func(t *testing.T) {
+		t.Parallel()
+
+		header := http.Header{"content-type": {"text/plain"}, "Set-Cookie": {"a=1", "b=2"}}
+		resp := NewResponse(http.StatusCreated, []byte("created"), header)
+
+		if resp.StatusCode != http.StatusCreated || resp.Status != "Created" {
+			t.Fatalf("status mismatch: got %d %q", resp.StatusCode, resp.Status)
+		}
+		if got := resp.Header.Get("Content-Type"); got != "text/plain" {
+			t.Fatalf("expected canonical Content-Type header, got %q", got)
+		}
+		if got := resp.Header.Values("Set-Cookie"); !slices.Equal(got, []string{"a=1", "b=2"}) {
+			t.Fatalf("Set-Cookie mismatch: got %q", got)
+		}
+		if resp.ContentLength != int64(len("created")) {
+			t.Fatalf("expected ContentLength %d, got %d", len("created"), resp.ContentLength)
+		}
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("failed to read body: %v", err)
+		}
+		if string(body) != "created" {
+			t.Fatalf("body mismatch: got %q", body)
+		}
+		if err := resp.Body.Close(); err != nil {
+			t.Fatalf("Close returned error: %v", err)
+		}
+
+		// The response must not share the caller's header map.
+		header.Set("Content-Type", "application/json")
+		if got := resp.Header.Get("Content-Type"); got != "text/plain" {
+			t.Fatalf("response header changed with caller map: got %q", got)
+		}
+	})
+
+	t.Run("empty body", func(t *testing.T) {
+		t.Parallel()
+
+		resp := NewResponse(http.StatusNoContent, nil, nil)
+		if resp.Body != nil || resp.Header == nil || resp.Status != "No Content" {
+			t.Fatalf("unexpected response: %+v", resp)
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		t.Parallel()
+
+		resp, err := JSONResponse(http.StatusOK, map[string]string{"message": "success"})
+		if err != nil {
+			t.Fatalf("JSONResponse returned error: %v", err)
+		}
+		if got := resp.Header.Get("Content-Type"); got != "application/json" {
+			t.Fatalf("Content-Type mismatch: got %q", got)
+		}
+
+		var out struct {
+			Message string `json:"message"`
+		}
+		if err := resp.DecodeJSON(&out); err != nil {
+			t.Fatalf("DecodeJSON returned error: %v", err)
+		}
+		if out.Message != "success" {
+			t.Fatalf("decoded message mismatch: got %q", out.Message)
+		}
+
+		if _, err := JSONResponse(http.StatusOK, make(chan int)); !errors.Is(err, ErrEncodeJSON) {
+			t.Fatalf("expected %v, got %v", ErrEncodeJSON, err)
+		}
+	})
+}
+
+func TestZeroValueClient(t *testing.T) {
+	t.Parallel()
+
+	// A client not built with New has no host call; it must fail rather than panic.
+	var client HTTPClient
+	_, err := client.Get("http://example.com")
+	if !errors.Is(err, sdk.ErrNilHostCall) || !errors.Is(err, sdk.ErrHostCall) {
+		t.Fatalf("expected ErrNilHostCall wrapped in ErrHostCall, got %v", err)
+	}
+}
@@ -0,0 +1,39 @@
+/*
+Package httpclienttest builds canned httpclient host responses for tests.
+
+Each helper returns the encoded payload the httpclient expects from the host,
+ready to return from a hostmock Response or Responses entry or a hand-written
+host call. Like kvtest and sqltest, it takes the status the response carries,
+built with sdktest.OK, sdktest.Partial, or sdktest.Failed; a failed status with
+no upstream reply reports a host that could not complete the request at all.
+Bare Ping statuses come from sdktest.StatusResponse:
+
+	mock, _ := hostmock.New(hostmock.Config{
+	  Response: func() []byte { return httpclienttest.Response(sdktest.OK(), http.StatusOK, nil, []byte("ok")) },
+	})
+*/
+package httpclienttest
+
+import (
+	"net/http"
+
+	sdkproto "github.com/tarmac-project/protobuf-go/sdk"
+	proto "github.com/tarmac-project/protobuf-go/sdk/http"
+)
+
+// Response encodes a response carrying status and an upstream reply with code,
+// header, and body.
+func Response(status *sdkproto.Status, code int, header http.Header, body []byte) []byte {
+	headers := make(map[string]*proto.Header, len(header))
+	for name, values := range header {
+		headers[name] = &proto.Header{Values: values}
+	}
+
+	b, _ := (&proto.HTTPClientResponse{
+		Status:  status,
+		Code:    int32(code),
+		Headers: headers,
+		Body:    body,
+	}).MarshalVT()
+	return b
+}
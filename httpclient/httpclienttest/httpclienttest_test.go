@@ -0,0 +1,63 @@
+package httpclienttest_test
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+
+	sdk "github.com/tarmac-project/sdk"
+	"github.com/tarmac-project/sdk/hostmock"
+	"github.com/tarmac-project/sdk/httpclient"
+	"github.com/tarmac-project/sdk/httpclient/httpclienttest"
+	"github.com/tarmac-project/sdk/sdktest"
+)
+
+func TestResponses(t *testing.T) {
+	t.Parallel()
+
+	mock, err := hostmock.New(hostmock.Config{
+		Functions: map[string]hostmock.FunctionConfig{
+			"call": {Responses: []func() ([]byte, error){
+				func() ([]byte, error) {
+					header := http.Header{"Content-Type": {"text/plain"}}
+					return httpclienttest.Response(sdktest.OK(), http.StatusCreated, header, []byte("created")), nil
+				},
+				func() ([]byte, error) {
+					return httpclienttest.Response(sdktest.Failed(sdk.StatusError, "unreachable"), 0, nil, nil), nil
+				},
+			}},
+			sdk.PingFunction: {Response: func() []byte { return sdktest.StatusResponse(sdktest.OK()) }},
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to create hostmock: %v", err)
+	}
+
+	client, err := httpclient.New(httpclient.Config{HostCall: mock.HostCall})
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	resp, err := client.Get("https://example.com/")
+	if err != nil {
+		t.Fatalf("Get returned error: %v", err)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("failed to read body: %v", err)
+	}
+	if resp.StatusCode != http.StatusCreated || resp.Header.Get("Content-Type") != "text/plain" ||
+		string(body) != "created" {
+		t.Fatalf("unexpected response: %d %v %q", resp.StatusCode, resp.Header, body)
+	}
+
+	var statusErr *sdk.HostStatusError
+	if _, err := client.Get("https://example.com/"); !errors.As(err, &statusErr) || statusErr.Message != "unreachable" {
+		t.Fatalf("Get: expected host status message %q, got %v", "unreachable", err)
+	}
+
+	if err := client.Ping(); err != nil {
+		t.Fatalf("Ping returned error: %v", err)
+	}
+}
@@ -0,0 +1,45 @@
+package httpclient
+
+import (
+	"bytes"
+	"sync"
+)
+
+// bodyPool recycles response body buffers for clients with
+// Config.PoolResponseBodies set.
+var bodyPool = sync.Pool{
+	New: func() any { return new([]byte) },
+}
+
+// pooledBody is a response body backed by a buffer from bodyPool. Close
+// returns the buffer to the pool; reads after Close report io.EOF.
+type pooledBody struct {
+	buf    *[]byte
+	reader bytes.Reader
+}
+
+// newPooledBody wraps data, which must be backed by buf, as a response body.
+func newPooledBody(buf *[]byte, data []byte) *pooledBody {
+	*buf = data
+	b := &pooledBody{buf: buf}
+	b.reader.Reset(data)
+	return b
+}
+
+// Read reads from the pooled buffer.
+func (b *pooledBody) Read(p []byte) (int, error) {
+	return b.reader.Read(p)
+}
+
+// Close releases the buffer back to the pool. It is safe to call more than once.
+func (b *pooledBody) Close() error {
+	if b.buf == nil {
+		return nil
+	}
+
+	b.reader.Reset(nil)
+	bodyPool.Put(b.buf)
+	b.buf = nil
+
+	return nil
+}
@@ -0,0 +1,95 @@
+package httpclient
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	sdkproto "github.com/tarmac-project/protobuf-go/sdk"
+	proto "github.com/tarmac-project/protobuf-go/sdk/http"
+	"github.com/tarmac-project/sdk/hostmock"
+)
+
+// bodyResponse returns a host response carrying body.
+func bodyResponse(body []byte) func() ([]byte, error) {
+	return func() ([]byte, error) {
+		resp := &proto.HTTPClientResponse{
+			Status: &sdkproto.Status{Status: "OK", Code: 200},
+			Code:   200,
+			Body:   body,
+		}
+		return resp.MarshalVT()
+	}
+}
+
+func TestPoolResponseBodies(t *testing.T) {
+	bodies := [][]byte{
+		bytes.Repeat([]byte("a"), 4096),
+		[]byte("short"),
+		bytes.Repeat([]byte("b"), 8192),
+		nil,
+		[]byte("after empty"),
+	}
+
+	responses := make([]func() ([]byte, error), 0, len(bodies))
+	for _, body := range bodies {
+		responses = append(responses, bodyResponse(body))
+	}
+
+	mock, err := hostmock.New(hostmock.Config{Responses: responses})
+	if err != nil {
+		t.Fatalf("failed to create hostmock: %v", err)
+	}
+
+	client, err := New(Config{HostCall: mock.HostCall, PoolResponseBodies: true})
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	// Keep the first body open while later responses recycle buffers.
+	first, err := client.Get("http://example.com/0")
+	if err != nil {
+		t.Fatalf("request 0 returned error: %v", err)
+	}
+
+	for i, want := range bodies[1:] {
+		resp, err := client.Get("http://example.com/")
+		if err != nil {
+			t.Fatalf("request %d returned error: %v", i+1, err)
+		}
+
+		if want == nil {
+			if resp.Body != nil {
+				t.Fatalf("request %d: expected nil body", i+1)
+			}
+			continue
+		}
+
+		got, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("request %d: read body: %v", i+1, err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Fatalf("request %d: body mismatch: want %d bytes, got %d bytes", i+1, len(want), len(got))
+		}
+
+		if err := resp.Body.Close(); err != nil {
+			t.Fatalf("request %d: close: %v", i+1, err)
+		}
+		if err := resp.Body.Close(); err != nil {
+			t.Fatalf("request %d: second close: %v", i+1, err)
+		}
+		if n, err := resp.Body.Read(make([]byte, 1)); n != 0 || err != io.EOF {
+			t.Fatalf("request %d: expected EOF after close, got %d, %v", i+1, n, err)
+		}
+	}
+
+	got, err := io.ReadAll(first.Body)
+	if err != nil {
+		t.Fatalf("read first body: %v", err)
+	}
+	if !bytes.Equal(got, bodies[0]) {
+		t.Fatal("open body was modified by later responses")
+	}
+	_ = first.Body.Close()
+}
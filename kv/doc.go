@@ -8,7 +8,28 @@ fall back to sensible defaults such as the `sdk.DefaultNamespace` and the
 default waPC host call.
 
 Typical usage is to construct a Client with New, then invoke Set, Get, Delete,
-and Keys. Tests can inject custom host behaviour with Config.HostCall to
-exercise failure paths without a real host.
+and Keys. Lookup reports a missing key as a false found result rather than
+ErrKeyNotFound. SetJSON and GetJSON wrap Set and Get for structured values, reporting
+encoding failures with ErrMarshalValue and ErrUnmarshalValue so they remain
+distinct from host errors. SetJSON sorts map keys, so equal values always store
+identical bytes. Hash returns a stable SHA-256 digest for ETag-style caching,
+and SetIfChanged skips the write when the stored value is already identical.
+KeysWithPrefix and KeysPage filter and page the key list in the client, since
+the host protocol has no server-side filter.
+GetMany, SetMany, and DeleteMany apply an operation to several keys, continuing
+past failures and reporting them per key in a *BatchError.
+
+Host calls are bounded by Config.Timeout, or by the SDK DefaultTimeout when it
+is unset; an expired call returns an error matching context.DeadlineExceeded.
+GetContext, SetContext, DeleteContext, and KeysContext additionally stop
+waiting once the given context is done, returning an error that matches its
+context error. Ping issues a lightweight health check so functions can fail
+fast at startup when the capability is unavailable.
+
+Tests can inject custom host behaviour with Config.HostCall to exercise failure
+paths without a real host.
+
+The kvtest package builds canned host responses for tests that script the
+host through Config.HostCall or hostmock.
 */
 package kv
@@ -1,14 +1,26 @@
 package kv
 
 import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
+	"strings"
+	"time"
 
+	sdkproto "github.com/tarmac-project/protobuf-go/sdk"
 	kvstore "github.com/tarmac-project/protobuf-go/sdk/kvstore"
 	sdk "github.com/tarmac-project/sdk"
 	wapc "github.com/wapc/wapc-guest-tinygo"
 )
 
+// capabilityName is the default host capability name for kvstore operations.
+const capabilityName = "kvstore"
+
 // Client represents a key-value capability client.
 type Client interface {
 	// Config returns the runtime configuration used by the client.
@@ -18,6 +30,10 @@ type Client interface {
 	// ErrKeyNotFound is returned.
 	Get(key string) ([]byte, error)
 
+	// Lookup returns the value for key and whether it was found. A missing
+	// key reports false with a nil error.
+	Lookup(key string) ([]byte, bool, error)
+
 	// Set stores value under key. It returns an error for invalid inputs
 	// or host call failures.
 	Set(key string, value []byte) error
@@ -28,6 +44,52 @@ type Client interface {
 	// Keys returns a snapshot of keys in the store.
 	Keys() ([]string, error)
 
+	// GetContext is like Get but stops waiting on the host once ctx is done.
+	GetContext(ctx context.Context, key string) ([]byte, error)
+
+	// SetContext is like Set but stops waiting on the host once ctx is done.
+	SetContext(ctx context.Context, key string, value []byte) error
+
+	// DeleteContext is like Delete but stops waiting on the host once ctx is done.
+	DeleteContext(ctx context.Context, key string) error
+
+	// KeysContext is like Keys but stops waiting on the host once ctx is done.
+	KeysContext(ctx context.Context) ([]string, error)
+
+	// KeysWithPrefix returns the sorted keys that start with prefix.
+	KeysWithPrefix(prefix string) ([]string, error)
+
+	// KeysPage returns up to limit sorted keys that start with prefix and sort
+	// after cursor, along with the cursor for the next page. An empty next
+	// cursor signals the end of the list.
+	KeysPage(prefix, cursor string, limit int) ([]string, string, error)
+
+	// GetJSON retrieves the value for key and decodes it as JSON into out. If
+	// the key is not found, ErrKeyNotFound is returned.
+	GetJSON(key string, out any) error
+
+	// SetJSON encodes v as JSON and stores it under key.
+	SetJSON(key string, v any) error
+
+	// SetIfChanged stores value under key unless the stored value is already
+	// identical, reporting whether a write was made.
+	SetIfChanged(key string, value []byte) (bool, error)
+
+	// GetMany returns the values for keys. Keys that fail, including missing
+	// keys, are reported in a *BatchError alongside the values that succeeded.
+	GetMany(keys []string) (map[string][]byte, error)
+
+	// SetMany stores each item, continuing past failures, and reports the keys
+	// that failed in a *BatchError.
+	SetMany(items map[string][]byte) error
+
+	// DeleteMany removes each key, continuing past failures, and reports the
+	// keys that failed in a *BatchError.
+	DeleteMany(keys []string) error
+
+	// Ping checks that the host kvstore capability is available.
+	Ping() error
+
 	// Close releases resources held by the client.
 	Close() error
 }
@@ -39,6 +101,20 @@ type Config struct {
 
 	// HostCall overrides the waPC host function used for requests.
 	HostCall func(string, string, string, []byte) ([]byte, error)
+
+	// AllowEmptyValues lets Set store empty values, such as tombstones or flags.
+	// Empty keys are still rejected. By default Set returns ErrInvalidValue for
+	// empty values.
+	AllowEmptyValues bool
+
+	// Timeout bounds each host call. When zero, SDKConfig.DefaultTimeout is
+	// used; a negative value disables the timeout even when a default is set.
+	Timeout time.Duration
+
+	// Capability overrides the host capability name used for host calls, for
+	// hosts that register the capability under a custom name. When empty,
+	// "kvstore" is used.
+	Capability string
 }
 
 // StoreClient implements Client using a configured waPC host call.
@@ -48,6 +124,15 @@ type StoreClient struct {
 
 	// hostCall issues waPC invocations on behalf of the client.
 	hostCall func(string, string, string, []byte) ([]byte, error)
+
+	// allowEmptyValues disables the empty-value check in Set.
+	allowEmptyValues bool
+
+	// timeout bounds each host call; zero disables the bound.
+	timeout time.Duration
+
+	// capability is the host capability name used for host calls.
+	capability string
 }
 
 // Ensure client implements the Client interface at compile time.
@@ -67,19 +152,86 @@ var (
 
 	// ErrKeyNotFound indicates that the requested key does not exist.
 	ErrKeyNotFound = errors.New("key not found in store")
-)
 
-const (
-	// statusOK indicates a successful operation.
-	statusOK = int32(200)
+	// ErrInvalidLimit indicates that a page limit is zero or negative.
+	ErrInvalidLimit = errors.New("limit must be greater than zero")
 
-	// statusNotFound indicates that the requested key does not exist.
-	statusNotFound = int32(404)
+	// ErrMarshalValue wraps failures while encoding a value as JSON.
+	ErrMarshalValue = errors.New("failed to marshal value")
 
-	// statusError indicates that an error occurred during the operation.
-	statusError = int32(500)
+	// ErrUnmarshalValue wraps failures while decoding a stored value as JSON.
+	ErrUnmarshalValue = errors.New("failed to unmarshal value")
 )
 
+// BatchError reports the keys that failed during a batch operation. The
+// operations for all other keys were applied.
+type BatchError struct {
+	// Operation names the batch operation, such as "set".
+	Operation string
+
+	// Errors maps each failing key to its error.
+	Errors map[string]error
+}
+
+// Error returns the failing keys and their errors in key order.
+func (e *BatchError) Error() string {
+	if e == nil {
+		return "kv batch operation failed"
+	}
+
+	keys := e.Keys()
+	var b strings.Builder
+	fmt.Fprintf(&b, "kv %s failed for %d key(s)", e.Operation, len(keys))
+	for _, key := range keys {
+		fmt.Fprintf(&b, "; %q: %v", key, e.Errors[key])
+	}
+
+	return b.String()
+}
+
+// Keys returns the failing keys in sorted order.
+func (e *BatchError) Keys() []string {
+	if e == nil {
+		return nil
+	}
+
+	keys := make([]string, 0, len(e.Errors))
+	for key := range e.Errors {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	return keys
+}
+
+// Unwrap returns the error of the first failing key in key order so errors.Is
+// and errors.As can inspect it.
+func (e *BatchError) Unwrap() error {
+	keys := e.Keys()
+	if len(keys) == 0 {
+		return nil
+	}
+
+	return e.Errors[keys[0]]
+}
+
+// add records err for key, allocating the error map on first use.
+func (e *BatchError) add(key string, err error) {
+	if e.Errors == nil {
+		e.Errors = make(map[string]error)
+	}
+	e.Errors[key] = err
+}
+
+// errOrNil returns e when any key failed and nil otherwise.
+func (e *BatchError) errOrNil() error {
+	if len(e.Errors) == 0 {
+		return nil
+	}
+
+	return e
+}
+
 // New creates a new key-value client.
 func New(config Config) (*StoreClient, error) {
 	runtime := config.SDKConfig
@@ -92,12 +244,82 @@ func New(config Config) (*StoreClient, error) {
 		hostCall = wapc.HostCall
 	}
 
+	// Per-client timeouts take precedence over the runtime default.
+	timeout := config.Timeout
+	if timeout == 0 {
+		timeout = runtime.DefaultTimeout
+	}
+
+	capability := config.Capability
+	if capability == "" {
+		capability = capabilityName
+	}
+
 	return &StoreClient{
-		runtime:  runtime,
-		hostCall: hostCall,
+		runtime:          runtime,
+		hostCall:         hostCall,
+		allowEmptyValues: config.AllowEmptyValues,
+		timeout:          timeout,
+		capability:       capability,
 	}, nil
 }
 
+// call issues a kvstore host call bounded by ctx and the configured timeout.
+func (c *StoreClient) call(ctx context.Context, function string, payload []byte) ([]byte, error) {
+	return sdk.WithRequestContext(ctx, c.runtime).Call(c.hostCall, c.timeout, c.capability, function, payload)
+}
+
+// checkStatus maps status with sdk.StatusToError, so kv agrees with the other
+// clients on every code. Host error statuses are reported for operation,
+// keeping the status code and any accompanying host call error available to
+// errors.As. A missing status is an invalid response.
+func (c *StoreClient) checkStatus(operation string, status *sdkproto.Status, callErr error) error {
+	if status == nil {
+		return sdk.ErrHostResponseInvalid
+	}
+
+	err := sdk.StatusToError(status.GetCode(), status.GetStatus())
+	var hostErr *sdk.HostStatusError
+	if errors.As(err, &hostErr) {
+		return &sdk.HostStatusError{
+			Capability:  c.capability,
+			Operation:   operation,
+			Code:        hostErr.Code,
+			Message:     hostErr.Message,
+			HostCallErr: callErr,
+		}
+	}
+
+	return err
+}
+
+// Ping issues a lightweight health check call to the kvstore capability. It
+// returns sdk.ErrHostResponseInvalid for an empty response and otherwise the
+// status mapped by sdk.StatusToError.
+func (c *StoreClient) Ping() error {
+	respBytes, callErr := c.call(c.runtime.Context(), sdk.PingFunction, nil)
+	// Intentionally honor parseable host responses; only fail fast when no payload is available.
+	if callErr != nil && len(respBytes) == 0 {
+		return sdk.HostCallError(callErr)
+	}
+
+	// An empty payload decodes as a zero status code, which is no answer at all
+	// rather than an error status.
+	if len(respBytes) == 0 {
+		return sdk.ErrHostResponseInvalid
+	}
+
+	var status sdkproto.Status
+	if unmarshalErr := status.UnmarshalVT(respBytes); unmarshalErr != nil {
+		if callErr != nil {
+			return errors.Join(sdk.ErrHostCall, callErr, sdk.ErrHostResponseInvalid, unmarshalErr)
+		}
+		return errors.Join(sdk.ErrHostResponseInvalid, unmarshalErr)
+	}
+
+	return c.checkStatus(sdk.PingFunction, &status, callErr)
+}
+
 // Close releases resources associated with the client. It is a no-op.
 func (c *StoreClient) Close() error {
 	return nil
@@ -105,6 +327,12 @@ func (c *StoreClient) Close() error {
 
 // Get retrieves the value for key or returns ErrKeyNotFound if missing.
 func (c *StoreClient) Get(key string) ([]byte, error) {
+	return c.GetContext(c.runtime.Context(), key)
+}
+
+// GetContext retrieves the value for key, returning the context error if ctx is
+// done before the host responds.
+func (c *StoreClient) GetContext(ctx context.Context, key string) ([]byte, error) {
 	// Validate provided key
 	if key == "" {
 		return nil, ErrInvalidKey
@@ -112,16 +340,16 @@ func (c *StoreClient) Get(key string) ([]byte, error) {
 
 	// Construct and marshal the get request
 	req := &kvstore.KVStoreGet{Key: key}
-	b, err := req.MarshalVT()
+	b, err := sdk.MarshalRequest(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal get request: %w", err)
+		return nil, err
 	}
 
 	// Issue the host call and always inspect the payload.
-	respBytes, callErr := c.hostCall(c.runtime.Namespace, "kvstore", "get", b)
+	respBytes, callErr := c.call(ctx, "get", b)
 	// Intentionally honor parseable host responses; only fail fast when no payload is available.
 	if callErr != nil && len(respBytes) == 0 {
-		return nil, errors.Join(sdk.ErrHostCall, callErr)
+		return nil, sdk.HostCallError(callErr)
 	}
 
 	// Attempt to unmarshal whatever the host returned.
@@ -134,48 +362,48 @@ func (c *StoreClient) Get(key string) ([]byte, error) {
 	}
 
 	status := resp.GetStatus()
-	if status != nil && status.GetCode() == statusOK {
-		return resp.GetData(), nil
-	}
-
-	if status != nil && status.GetCode() == statusNotFound {
+	if status != nil && status.GetCode() == sdk.StatusNotFound {
 		return nil, ErrKeyNotFound
 	}
 
-	if status != nil && status.GetCode() == statusError {
-		if callErr != nil {
-			return nil, errors.Join(sdk.ErrHostError, callErr)
-		}
-		return nil, sdk.ErrHostError
+	if err := c.checkStatus("get", status, callErr); err != nil {
+		return nil, err
 	}
 
-	return nil, sdk.ErrHostResponseInvalid
+	return resp.GetData(), nil
 }
 
 // Set stores value under key. It returns ErrInvalidKey or ErrInvalidValue
-// for invalid inputs, or wraps host errors.
+// for invalid inputs, or wraps host errors. Empty values are rejected unless
+// Config.AllowEmptyValues is set.
 func (c *StoreClient) Set(key string, value []byte) error {
+	return c.SetContext(c.runtime.Context(), key, value)
+}
+
+// SetContext stores value under key, returning the context error if ctx is done
+// before the host responds.
+func (c *StoreClient) SetContext(ctx context.Context, key string, value []byte) error {
 	// Validate inputs
 	if key == "" {
 		return ErrInvalidKey
 	}
 
-	if len(value) == 0 {
+	if len(value) == 0 && !c.allowEmptyValues {
 		return ErrInvalidValue
 	}
 
 	// Construct and marshal the set request
 	req := &kvstore.KVStoreSet{Key: key, Data: value}
-	b, err := req.MarshalVT()
+	b, err := sdk.MarshalRequest(req)
 	if err != nil {
-		return fmt.Errorf("failed to marshal set request: %w", err)
+		return err
 	}
 
 	// Issue the host call and inspect the payload even on error
-	respBytes, callErr := c.hostCall(c.runtime.Namespace, "kvstore", "set", b)
+	respBytes, callErr := c.call(ctx, "set", b)
 	// Intentionally honor parseable host responses; only fail fast when no payload is available.
 	if callErr != nil && (len(respBytes) == 0) {
-		return errors.Join(sdk.ErrHostCall, callErr)
+		return sdk.HostCallError(callErr)
 	}
 
 	// Unmarshal the response from the host
@@ -187,23 +415,17 @@ func (c *StoreClient) Set(key string, value []byte) error {
 		return errors.Join(sdk.ErrHostResponseInvalid, unmarshalErr)
 	}
 
-	status := resp.GetStatus()
-	if status != nil && status.GetCode() == statusOK {
-		return nil
-	}
-
-	if status != nil && status.GetCode() == statusError {
-		if callErr != nil {
-			return errors.Join(sdk.ErrHostError, callErr)
-		}
-		return sdk.ErrHostError
-	}
-
-	return sdk.ErrHostResponseInvalid
+	return c.checkStatus("set", resp.GetStatus(), callErr)
 }
 
 // Delete removes key from the store. Deleting a non-existent key is not an error.
 func (c *StoreClient) Delete(key string) error {
+	return c.DeleteContext(c.runtime.Context(), key)
+}
+
+// DeleteContext removes key from the store, returning the context error if ctx
+// is done before the host responds.
+func (c *StoreClient) DeleteContext(ctx context.Context, key string) error {
 	// Validate key input up front to avoid unnecessary host calls.
 	if key == "" {
 		return ErrInvalidKey
@@ -211,16 +433,16 @@ func (c *StoreClient) Delete(key string) error {
 
 	// Marshal the delete request for the host capability.
 	req := &kvstore.KVStoreDelete{Key: key}
-	b, err := req.MarshalVT()
+	b, err := sdk.MarshalRequest(req)
 	if err != nil {
-		return fmt.Errorf("failed to marshal delete request: %w", err)
+		return err
 	}
 
 	// Invoke the host; keep the bytes for status parsing even when an error is returned.
-	respBytes, callErr := c.hostCall(c.runtime.Namespace, "kvstore", "delete", b)
+	respBytes, callErr := c.call(ctx, "delete", b)
 	// Intentionally honor parseable host responses; only fail fast when no payload is available.
 	if callErr != nil && len(respBytes) == 0 {
-		return errors.Join(sdk.ErrHostCall, callErr)
+		return sdk.HostCallError(callErr)
 	}
 
 	// Decode the payload; surface both host and decoding errors when applicable.
@@ -232,35 +454,35 @@ func (c *StoreClient) Delete(key string) error {
 		return errors.Join(sdk.ErrHostResponseInvalid, unmarshalErr)
 	}
 
+	// Deleting a missing key is not an error.
 	status := resp.GetStatus()
-	if status != nil && (status.GetCode() == statusOK || status.GetCode() == statusNotFound) {
+	if status != nil && status.GetCode() == sdk.StatusNotFound {
 		return nil
 	}
 
-	if status != nil && status.GetCode() == statusError {
-		if callErr != nil {
-			return errors.Join(sdk.ErrHostError, callErr)
-		}
-		return sdk.ErrHostError
-	}
-
-	return sdk.ErrHostResponseInvalid
+	return c.checkStatus("delete", status, callErr)
 }
 
 // Keys returns a snapshot of keys currently in the store.
 func (c *StoreClient) Keys() ([]string, error) {
+	return c.KeysContext(c.runtime.Context())
+}
+
+// KeysContext returns a snapshot of keys currently in the store, returning the
+// context error if ctx is done before the host responds.
+func (c *StoreClient) KeysContext(ctx context.Context) ([]string, error) {
 	// Build a request that asks the host to return a protobuf-encoded key list.
 	req := &kvstore.KVStoreKeys{ReturnProto: true}
-	b, err := req.MarshalVT()
+	b, err := sdk.MarshalRequest(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal keys request: %w", err)
+		return nil, err
 	}
 
 	// Execute the host call; retain bytes even when the host reports an error.
-	respBytes, callErr := c.hostCall(c.runtime.Namespace, "kvstore", "keys", b)
+	respBytes, callErr := c.call(ctx, "keys", b)
 	// Intentionally honor parseable host responses; only fail fast when no payload is available.
 	if callErr != nil && len(respBytes) == 0 {
-		return nil, errors.Join(sdk.ErrHostCall, callErr)
+		return nil, sdk.HostCallError(callErr)
 	}
 
 	// Decode the protobuf payload and combine errors if both occur.
@@ -272,17 +494,198 @@ func (c *StoreClient) Keys() ([]string, error) {
 		return nil, errors.Join(sdk.ErrHostResponseInvalid, unmarshalErr)
 	}
 
-	status := resp.GetStatus()
-	if status != nil && status.GetCode() == statusOK {
-		return resp.GetKeys(), nil
+	if err := c.checkStatus("keys", resp.GetStatus(), callErr); err != nil {
+		return nil, err
 	}
 
-	if status != nil && status.GetCode() == statusError {
-		if callErr != nil {
-			return nil, errors.Join(sdk.ErrHostError, callErr)
+	return resp.GetKeys(), nil
+}
+
+// KeysWithPrefix returns the sorted keys that start with prefix. An empty prefix
+// matches every key.
+//
+// The kvstore capability has no server-side filter, so the full key list is
+// fetched from the host and filtered by the client.
+func (c *StoreClient) KeysWithPrefix(prefix string) ([]string, error) {
+	keys, err := c.Keys()
+	if err != nil {
+		return nil, err
+	}
+
+	matched := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if strings.HasPrefix(key, prefix) {
+			matched = append(matched, key)
+		}
+	}
+	slices.Sort(matched)
+
+	return matched, nil
+}
+
+// KeysPage returns up to limit sorted keys that start with prefix and sort
+// after cursor. Pass an empty cursor for the first page and the returned next
+// cursor for each following page; an empty next cursor signals the end of the
+// list. It returns ErrInvalidLimit when limit is not positive.
+//
+// Paging is performed by the client over KeysWithPrefix, so each page fetches
+// the full key list from the host. Keys added or removed between pages may be
+// skipped or returned, but a key is never returned twice.
+func (c *StoreClient) KeysPage(prefix, cursor string, limit int) ([]string, string, error) {
+	if limit <= 0 {
+		return nil, "", ErrInvalidLimit
+	}
+
+	keys, err := c.KeysWithPrefix(prefix)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// Skip every key up to and including the cursor.
+	start := 0
+	if cursor != "" {
+		var found bool
+		start, found = slices.BinarySearch(keys, cursor)
+		if found {
+			start++
+		}
+	}
+
+	end := min(start+limit, len(keys))
+	page := keys[start:end]
+
+	next := ""
+	if end < len(keys) {
+		next = page[len(page)-1]
+	}
+
+	return page, next, nil
+}
+
+// Lookup retrieves the value for key, reporting whether it exists. A missing
+// key returns false with a nil error; invalid keys and host failures return
+// the same errors as Get.
+func (c *StoreClient) Lookup(key string) ([]byte, bool, error) {
+	data, err := c.Get(key)
+	if errors.Is(err, ErrKeyNotFound) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	return data, true, nil
+}
+
+// GetJSON retrieves the value for key and decodes it as JSON into out. Host and
+// lookup errors are returned as-is, while decoding failures wrap ErrUnmarshalValue.
+func (c *StoreClient) GetJSON(key string, out any) error {
+	data, err := c.Get(key)
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(data, out); err != nil {
+		return errors.Join(ErrUnmarshalValue, err)
+	}
+
+	return nil
+}
+
+// SetJSON encodes v as JSON and stores it under key. Encoding failures wrap
+// ErrMarshalValue and are returned before any host call is made.
+//
+// The encoding is deterministic: map keys are sorted and struct fields keep
+// their declaration order, so equal values always store identical bytes.
+func (c *StoreClient) SetJSON(key string, v any) error {
+	// Validate the key before encoding so invalid input fails consistently with Set.
+	if key == "" {
+		return ErrInvalidKey
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return errors.Join(ErrMarshalValue, err)
+	}
+
+	return c.Set(key, data)
+}
+
+// Hash returns the hex-encoded SHA-256 digest of value. Equal values always
+// hash the same, so the result can serve as an ETag for stored values.
+func Hash(value []byte) string {
+	sum := sha256.Sum256(value)
+	return hex.EncodeToString(sum[:])
+}
+
+// SetIfChanged stores value under key unless the stored value already has the
+// same content, and reports whether a write was made. The host has no
+// conditional write, so this reads the current value first; a concurrent writer
+// can still change the key between the read and the write. Missing keys are
+// always written.
+func (c *StoreClient) SetIfChanged(key string, value []byte) (bool, error) {
+	current, err := c.Get(key)
+	switch {
+	case errors.Is(err, ErrKeyNotFound):
+	case err != nil:
+		return false, err
+	case bytes.Equal(current, value):
+		return false, nil
+	}
+
+	if err := c.Set(key, value); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// GetMany returns the values for keys. Failing keys, including those not found,
+// are reported in a *BatchError while the remaining values are still returned.
+func (c *StoreClient) GetMany(keys []string) (map[string][]byte, error) {
+	values := make(map[string][]byte, len(keys))
+	batchErr := &BatchError{Operation: "get"}
+
+	for _, key := range keys {
+		value, err := c.Get(key)
+		if err != nil {
+			batchErr.add(key, err)
+			continue
+		}
+		values[key] = value
+	}
+
+	return values, batchErr.errOrNil()
+}
+
+// SetMany stores each item in key order. Items that fail are reported in a
+// *BatchError; all other items are still stored.
+func (c *StoreClient) SetMany(items map[string][]byte) error {
+	keys := make([]string, 0, len(items))
+	for key := range items {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	batchErr := &BatchError{Operation: "set"}
+	for _, key := range keys {
+		if err := c.Set(key, items[key]); err != nil {
+			batchErr.add(key, err)
+		}
+	}
+
+	return batchErr.errOrNil()
+}
+
+// DeleteMany removes each key. Keys that fail are reported in a *BatchError;
+// all other keys are still removed.
+func (c *StoreClient) DeleteMany(keys []string) error {
+	batchErr := &BatchError{Operation: "delete"}
+	for _, key := range keys {
+		if err := c.Delete(key); err != nil {
+			batchErr.add(key, err)
 		}
-		return nil, sdk.ErrHostError
 	}
 
-	return nil, sdk.ErrHostResponseInvalid
+	return batchErr.errOrNil()
 }
@@ -2,15 +2,21 @@ package kv
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
+	"maps"
 	"slices"
+	"strings"
 	"testing"
+	"time"
 
 	sdkproto "github.com/tarmac-project/protobuf-go/sdk"
 	proto "github.com/tarmac-project/protobuf-go/sdk/kvstore"
 	sdk "github.com/tarmac-project/sdk"
 	"github.com/tarmac-project/sdk/hostmock"
+	"github.com/tarmac-project/sdk/kv/kvtest"
+	"github.com/tarmac-project/sdk/sdktest"
 )
 
 func TestNew(t *testing.T) {
@@ -59,35 +65,13 @@ func TestKVInterface(t *testing.T) {
 		capability = "kvstore"
 	)
 
-	buildHost := func(t *testing.T, configs map[string]hostmock.Config) func(string, string, string, []byte) ([]byte, error) {
-		t.Helper()
-		mocks := make(map[string]*hostmock.Mock)
-		for fn, cfg := range configs {
-			cfg.ExpectedNamespace = namespace
-			cfg.ExpectedCapability = capability
-			cfg.ExpectedFunction = fn
-			mock, err := hostmock.New(cfg)
-			if err != nil {
-				t.Fatalf("hostmock for %s: %v", fn, err)
-			}
-			mocks[fn] = mock
-		}
-		return func(ns, capabilityName, fn string, payload []byte) ([]byte, error) {
-			mock, ok := mocks[fn]
-			if !ok {
-				return nil, fmt.Errorf("unexpected host function %q", fn)
-			}
-			return mock.HostCall(ns, capabilityName, fn, payload)
-		}
-	}
-
 	tt := []struct {
 		name           string
 		key            string
 		value          []byte
 		wantKeys       []string
 		expectedErrors map[string]error
-		hostConfigs    map[string]hostmock.Config
+		hostConfigs    map[string]hostmock.FunctionConfig
 	}{
 		{
 			name:     "Valid Key/Value",
@@ -100,7 +84,7 @@ func TestKVInterface(t *testing.T) {
 				"DELETE": nil,
 				"KEYS":   nil,
 			},
-			hostConfigs: map[string]hostmock.Config{
+			hostConfigs: map[string]hostmock.FunctionConfig{
 				"set": {
 					PayloadValidator: func(payload []byte) error {
 						var req proto.KVStoreSet
@@ -181,7 +165,7 @@ func TestKVInterface(t *testing.T) {
 				"DELETE": ErrInvalidKey,
 				"KEYS":   nil,
 			},
-			hostConfigs: map[string]hostmock.Config{
+			hostConfigs: map[string]hostmock.FunctionConfig{
 				"keys": {
 					Response: func() []byte {
 						resp := &proto.KVStoreKeysResponse{
@@ -205,7 +189,7 @@ func TestKVInterface(t *testing.T) {
 				"DELETE": nil,
 				"KEYS":   nil,
 			},
-			hostConfigs: map[string]hostmock.Config{
+			hostConfigs: map[string]hostmock.FunctionConfig{
 				"get": {
 					PayloadValidator: func(payload []byte) error {
 						var req proto.KVStoreGet
@@ -258,8 +242,16 @@ func TestKVInterface(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
 
-			hostCall := buildHost(t, tc.hostConfigs)
-			client, err := New(Config{SDKConfig: sdk.RuntimeConfig{Namespace: namespace}, HostCall: hostCall})
+			mock, err := hostmock.New(hostmock.Config{
+				ExpectedNamespace:  namespace,
+				ExpectedCapability: capability,
+				Functions:          tc.hostConfigs,
+			})
+			if err != nil {
+				t.Fatalf("hostmock.New returned error: %v", err)
+			}
+
+			client, err := New(Config{SDKConfig: sdk.RuntimeConfig{Namespace: namespace}, HostCall: mock.HostCall})
 			if err != nil {
 				t.Fatalf("New returned error: %v", err)
 			}
@@ -499,7 +491,7 @@ func TestKVClientHostMock(t *testing.T) {
 				wantErr: sdk.ErrHostError,
 			},
 			{
-				name:  "invalid payload",
+				name:  "bad input status",
 				key:   "key1",
 				value: []byte("value1"),
 				mockConfig: hostmock.Config{
@@ -514,7 +506,7 @@ func TestKVClientHostMock(t *testing.T) {
 						return b
 					},
 				},
-				wantErr: sdk.ErrHostResponseInvalid,
+				wantErr: sdk.ErrHostError,
 			},
 			{
 				name:  "invalid response",
@@ -728,3 +720,1258 @@ func TestKVClientHostMock(t *testing.T) {
 		}
 	})
 }
+
+func TestJSONHelpers(t *testing.T) {
+	t.Parallel()
+
+	type record struct {
+		Name  string `json:"name"`
+		Count int    `json:"count"`
+	}
+
+	t.Run("round trip", func(t *testing.T) {
+		t.Parallel()
+
+		client := newStoreClient(t, map[string][]byte{})
+
+		want := record{Name: "widget", Count: 3}
+		if err := client.SetJSON("item", want); err != nil {
+			t.Fatalf("SetJSON returned error: %v", err)
+		}
+
+		var got record
+		if err := client.GetJSON("item", &got); err != nil {
+			t.Fatalf("GetJSON returned error: %v", err)
+		}
+		if got != want {
+			t.Fatalf("round trip mismatch: want %+v got %+v", want, got)
+		}
+	})
+
+	tt := []struct {
+		name    string
+		store   map[string][]byte
+		invoke  func(Client) error
+		wantErr error
+		notErr  error
+	}{
+		{
+			name:    "GetJSON missing key",
+			store:   map[string][]byte{},
+			invoke:  func(c Client) error { return c.GetJSON("missing", &record{}) },
+			wantErr: ErrKeyNotFound,
+			notErr:  ErrUnmarshalValue,
+		},
+		{
+			name:    "GetJSON invalid key",
+			store:   map[string][]byte{},
+			invoke:  func(c Client) error { return c.GetJSON("", &record{}) },
+			wantErr: ErrInvalidKey,
+		},
+		{
+			name:    "GetJSON malformed value",
+			store:   map[string][]byte{"item": []byte("not-json")},
+			invoke:  func(c Client) error { return c.GetJSON("item", &record{}) },
+			wantErr: ErrUnmarshalValue,
+			notErr:  sdk.ErrHostError,
+		},
+		{
+			name:    "SetJSON unsupported value",
+			store:   map[string][]byte{},
+			invoke:  func(c Client) error { return c.SetJSON("item", make(chan int)) },
+			wantErr: ErrMarshalValue,
+			notErr:  sdk.ErrHostCall,
+		},
+		{
+			name:    "SetJSON invalid key",
+			store:   map[string][]byte{},
+			invoke:  func(c Client) error { return c.SetJSON("", record{}) },
+			wantErr: ErrInvalidKey,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tc.invoke(newStoreClient(t, tc.store))
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("unexpected error: want %v got %v", tc.wantErr, err)
+			}
+			if tc.notErr != nil && errors.Is(err, tc.notErr) {
+				t.Fatalf("unexpected error in chain: %v", tc.notErr)
+			}
+		})
+	}
+
+	t.Run("SetJSON is deterministic", func(t *testing.T) {
+		t.Parallel()
+
+		store := map[string][]byte{}
+		client := newStoreClient(t, store)
+
+		value := map[string]any{
+			"zeta":  1,
+			"alpha": []string{"b", "a"},
+			"mid":   map[string]int{"y": 2, "x": 1, "z": 3},
+		}
+
+		for _, key := range []string{"first", "second"} {
+			if err := client.SetJSON(key, value); err != nil {
+				t.Fatalf("SetJSON returned error: %v", err)
+			}
+		}
+
+		want := `{"alpha":["b","a"],"mid":{"x":1,"y":2,"z":3},"zeta":1}`
+		if string(store["first"]) != want || !bytes.Equal(store["first"], store["second"]) {
+			t.Fatalf("expected identical canonical bytes %s, got %s and %s", want, store["first"], store["second"])
+		}
+	})
+
+	t.Run("SetJSON host failure", func(t *testing.T) {
+		t.Parallel()
+
+		mock, err := hostmock.New(hostmock.Config{
+			ExpectedCapability: "kvstore",
+			ExpectedFunction:   "set",
+			Fail:               true,
+			Error:              errors.New("host failure"),
+		})
+		if err != nil {
+			t.Fatalf("failed to create host mock: %v", err)
+		}
+		client, err := New(Config{HostCall: mock.HostCall})
+		if err != nil {
+			t.Fatalf("New returned error: %v", err)
+		}
+
+		setErr := client.SetJSON("item", record{Name: "widget"})
+		if !errors.Is(setErr, sdk.ErrHostCall) || errors.Is(setErr, ErrMarshalValue) {
+			t.Fatalf("expected host call error only, got %v", setErr)
+		}
+	})
+}
+
+// newStoreClient builds a client backed by an in-memory fake host that serves
+// get, set, delete, and keys from store.
+func TestBatchOperations(t *testing.T) {
+	t.Parallel()
+
+	assertBatchError := func(t *testing.T, err error, op string, wantKeys []string, wantErr error) {
+		t.Helper()
+
+		var batchErr *BatchError
+		if !errors.As(err, &batchErr) {
+			t.Fatalf("expected *BatchError, got %T: %v", err, err)
+		}
+		if batchErr.Operation != op {
+			t.Fatalf("expected operation %q, got %q", op, batchErr.Operation)
+		}
+		if got := batchErr.Keys(); !slices.Equal(got, wantKeys) {
+			t.Fatalf("expected failing keys %q, got %q", wantKeys, got)
+		}
+		if !errors.Is(err, wantErr) {
+			t.Fatalf("expected error to match %v, got %v", wantErr, err)
+		}
+	}
+
+	t.Run("SetMany", func(t *testing.T) {
+		t.Parallel()
+
+		store := map[string][]byte{}
+		client := newStoreClient(t, store)
+
+		err := client.SetMany(map[string][]byte{"a": []byte("1"), "bad": nil, "c": []byte("3")})
+		assertBatchError(t, err, "set", []string{"bad"}, ErrInvalidValue)
+
+		if !bytes.Equal(store["a"], []byte("1")) || !bytes.Equal(store["c"], []byte("3")) {
+			t.Fatalf("expected successful items to be stored, got %q", store)
+		}
+		if _, ok := store["bad"]; ok {
+			t.Fatalf("expected failing item to be skipped")
+		}
+	})
+
+	t.Run("GetMany", func(t *testing.T) {
+		t.Parallel()
+
+		client := newStoreClient(t, map[string][]byte{"a": []byte("1"), "b": []byte("2")})
+
+		values, err := client.GetMany([]string{"a", "missing", "b"})
+		assertBatchError(t, err, "get", []string{"missing"}, ErrKeyNotFound)
+
+		want := map[string][]byte{"a": []byte("1"), "b": []byte("2")}
+		if !maps.EqualFunc(values, want, bytes.Equal) {
+			t.Fatalf("expected values %q, got %q", want, values)
+		}
+	})
+
+	t.Run("DeleteMany", func(t *testing.T) {
+		t.Parallel()
+
+		store := map[string][]byte{"a": []byte("1"), "b": []byte("2")}
+		client := newStoreClient(t, store)
+
+		err := client.DeleteMany([]string{"a", "", "b"})
+		assertBatchError(t, err, "delete", []string{""}, ErrInvalidKey)
+
+		if len(store) != 0 {
+			t.Fatalf("expected successful deletes to apply, got %q", store)
+		}
+	})
+
+	t.Run("all succeed", func(t *testing.T) {
+		t.Parallel()
+
+		client := newStoreClient(t, map[string][]byte{})
+
+		if err := client.SetMany(map[string][]byte{"a": []byte("1")}); err != nil {
+			t.Fatalf("SetMany returned error: %v", err)
+		}
+		if _, err := client.GetMany([]string{"a"}); err != nil {
+			t.Fatalf("GetMany returned error: %v", err)
+		}
+		if err := client.DeleteMany([]string{"a"}); err != nil {
+			t.Fatalf("DeleteMany returned error: %v", err)
+		}
+	})
+
+	t.Run("error message names keys", func(t *testing.T) {
+		t.Parallel()
+
+		err := &BatchError{Operation: "set", Errors: map[string]error{"b": ErrInvalidValue, "a": ErrInvalidKey}}
+		want := `kv set failed for 2 key(s); "a": key is invalid; "b": value is invalid`
+		if got := err.Error(); got != want {
+			t.Fatalf("expected %q, got %q", want, got)
+		}
+	})
+}
+
+func newStoreClient(t *testing.T, store map[string][]byte) *StoreClient {
+	t.Helper()
+
+	status := func(code int32) *sdkproto.Status {
+		return &sdkproto.Status{Code: code}
+	}
+
+	hostCall := func(_, capabilityName, fn string, payload []byte) ([]byte, error) {
+		if capabilityName != "kvstore" {
+			return nil, fmt.Errorf("unexpected capability %q", capabilityName)
+		}
+
+		switch fn {
+		case "get":
+			var req proto.KVStoreGet
+			if err := req.UnmarshalVT(payload); err != nil {
+				return nil, err
+			}
+			data, ok := store[req.GetKey()]
+			if !ok {
+				return (&proto.KVStoreGetResponse{Status: status(404)}).MarshalVT()
+			}
+			return (&proto.KVStoreGetResponse{Status: status(200), Data: data}).MarshalVT()
+		case "set":
+			var req proto.KVStoreSet
+			if err := req.UnmarshalVT(payload); err != nil {
+				return nil, err
+			}
+			store[req.GetKey()] = req.GetData()
+			return (&proto.KVStoreSetResponse{Status: status(200)}).MarshalVT()
+		case "delete":
+			var req proto.KVStoreDelete
+			if err := req.UnmarshalVT(payload); err != nil {
+				return nil, err
+			}
+			delete(store, req.GetKey())
+			return (&proto.KVStoreDeleteResponse{Status: status(200)}).MarshalVT()
+		case "keys":
+			keys := make([]string, 0, len(store))
+			for key := range store {
+				keys = append(keys, key)
+			}
+			slices.Sort(keys)
+			return (&proto.KVStoreKeysResponse{Status: status(200), Keys: keys}).MarshalVT()
+		default:
+			return nil, fmt.Errorf("unexpected host function %q", fn)
+		}
+	}
+
+	client, err := New(Config{HostCall: hostCall})
+	if err != nil {
+		t.Fatalf("New returned error: %v", err)
+	}
+	return client
+}
+
+func TestWireRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	ok := &sdkproto.Status{Status: "OK", Code: 200}
+
+	t.Run("KVStoreGet", func(t *testing.T) {
+		t.Parallel()
+		sdktest.AssertRoundTrip(t, &proto.KVStoreGet{Key: "key1"})
+	})
+
+	t.Run("KVStoreGetResponse", func(t *testing.T) {
+		t.Parallel()
+		sdktest.AssertRoundTrip(t, &proto.KVStoreGetResponse{Status: ok, Data: []byte("value")})
+	})
+
+	t.Run("KVStoreSet", func(t *testing.T) {
+		t.Parallel()
+		sdktest.AssertRoundTrip(t, &proto.KVStoreSet{Key: "key1", Data: []byte("value")})
+	})
+
+	t.Run("KVStoreSetResponse", func(t *testing.T) {
+		t.Parallel()
+		sdktest.AssertRoundTrip(t, &proto.KVStoreSetResponse{Status: ok})
+	})
+
+	t.Run("KVStoreDelete", func(t *testing.T) {
+		t.Parallel()
+		sdktest.AssertRoundTrip(t, &proto.KVStoreDelete{Key: "key1"})
+	})
+
+	t.Run("KVStoreDeleteResponse", func(t *testing.T) {
+		t.Parallel()
+		sdktest.AssertRoundTrip(t, &proto.KVStoreDeleteResponse{Status: ok})
+	})
+
+	t.Run("KVStoreKeys", func(t *testing.T) {
+		t.Parallel()
+		sdktest.AssertRoundTrip(t, &proto.KVStoreKeys{ReturnProto: true})
+	})
+
+	t.Run("KVStoreKeysResponse", func(t *testing.T) {
+		t.Parallel()
+		sdktest.AssertRoundTrip(t, &proto.KVStoreKeysResponse{Status: ok, Keys: []string{"a", "b"}})
+	})
+}
+
+func TestKeysPrefixAndPaging(t *testing.T) {
+	t.Parallel()
+
+	store := map[string][]byte{
+		"user:3":  []byte("c"),
+		"user:1":  []byte("a"),
+		"order:1": []byte("o"),
+		"user:2":  []byte("b"),
+		"user:10": []byte("j"),
+	}
+
+	t.Run("KeysWithPrefix", func(t *testing.T) {
+		t.Parallel()
+
+		tt := []struct {
+			name   string
+			prefix string
+			want   []string
+		}{
+			{"matching prefix", "user:", []string{"user:1", "user:10", "user:2", "user:3"}},
+			{"empty prefix", "", []string{"order:1", "user:1", "user:10", "user:2", "user:3"}},
+			{"no matches", "missing:", []string{}},
+		}
+
+		for _, tc := range tt {
+			t.Run(tc.name, func(t *testing.T) {
+				t.Parallel()
+
+				client := newStoreClient(t, maps.Clone(store))
+				got, err := client.KeysWithPrefix(tc.prefix)
+				if err != nil {
+					t.Fatalf("KeysWithPrefix returned error: %v", err)
+				}
+				if !slices.Equal(got, tc.want) {
+					t.Fatalf("unexpected keys: want %v got %v", tc.want, got)
+				}
+			})
+		}
+	})
+
+	t.Run("KeysPage walks to end", func(t *testing.T) {
+		t.Parallel()
+
+		client := newStoreClient(t, maps.Clone(store))
+
+		var (
+			pages  [][]string
+			cursor string
+		)
+		for {
+			page, next, err := client.KeysPage("user:", cursor, 3)
+			if err != nil {
+				t.Fatalf("KeysPage returned error: %v", err)
+			}
+			pages = append(pages, page)
+			if next == "" {
+				break
+			}
+			cursor = next
+		}
+
+		want := [][]string{{"user:1", "user:10", "user:2"}, {"user:3"}}
+		if !slices.EqualFunc(pages, want, slices.Equal) {
+			t.Fatalf("unexpected pages: want %v got %v", want, pages)
+		}
+	})
+
+	t.Run("KeysPage exact fit ends without cursor", func(t *testing.T) {
+		t.Parallel()
+
+		client := newStoreClient(t, maps.Clone(store))
+		page, next, err := client.KeysPage("user:", "", 4)
+		if err != nil {
+			t.Fatalf("KeysPage returned error: %v", err)
+		}
+		if len(page) != 4 || next != "" {
+			t.Fatalf("expected 4 keys and empty cursor, got %v and %q", page, next)
+		}
+	})
+
+	t.Run("KeysPage cursor for removed key", func(t *testing.T) {
+		t.Parallel()
+
+		client := newStoreClient(t, maps.Clone(store))
+		page, next, err := client.KeysPage("user:", "user:15", 10)
+		if err != nil {
+			t.Fatalf("KeysPage returned error: %v", err)
+		}
+		want := []string{"user:2", "user:3"}
+		if !slices.Equal(page, want) || next != "" {
+			t.Fatalf("unexpected page: want %v got %v (next %q)", want, page, next)
+		}
+	})
+
+	t.Run("KeysPage invalid limit", func(t *testing.T) {
+		t.Parallel()
+
+		client := newStoreClient(t, maps.Clone(store))
+		for _, limit := range []int{0, -1} {
+			if _, _, err := client.KeysPage("", "", limit); !errors.Is(err, ErrInvalidLimit) {
+				t.Fatalf("limit %d: expected %v, got %v", limit, ErrInvalidLimit, err)
+			}
+		}
+	})
+
+	t.Run("host error", func(t *testing.T) {
+		t.Parallel()
+
+		mock, err := hostmock.New(hostmock.Config{
+			ExpectedCapability: "kvstore",
+			ExpectedFunction:   "keys",
+			PayloadValidator: func(payload []byte) error {
+				var req proto.KVStoreKeys
+				if err := req.UnmarshalVT(payload); err != nil {
+					return err
+				}
+				if !req.GetReturnProto() {
+					return errors.New("expected ReturnProto to be set")
+				}
+				return nil
+			},
+			Fail:  true,
+			Error: errors.New("host failure"),
+		})
+		if err != nil {
+			t.Fatalf("failed to create host mock: %v", err)
+		}
+		client, err := New(Config{HostCall: mock.HostCall})
+		if err != nil {
+			t.Fatalf("New returned error: %v", err)
+		}
+
+		if _, err := client.KeysWithPrefix("user:"); !errors.Is(err, sdk.ErrHostCall) {
+			t.Fatalf("expected %v, got %v", sdk.ErrHostCall, err)
+		}
+		if _, _, err := client.KeysPage("user:", "", 1); !errors.Is(err, sdk.ErrHostCall) {
+			t.Fatalf("expected %v, got %v", sdk.ErrHostCall, err)
+		}
+	})
+}
+
+func TestSetAllowEmptyValues(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		name       string
+		allowEmpty bool
+		key        string
+		value      []byte
+		wantErr    error
+		wantCalled bool
+	}{
+		{name: "default rejects nil value", key: "flag", value: nil, wantErr: ErrInvalidValue},
+		{name: "default rejects empty value", key: "flag", value: []byte{}, wantErr: ErrInvalidValue},
+		{name: "default accepts value", key: "flag", value: []byte("on"), wantCalled: true},
+		{name: "allowed nil value", allowEmpty: true, key: "flag", value: nil, wantCalled: true},
+		{name: "allowed empty value", allowEmpty: true, key: "flag", value: []byte{}, wantCalled: true},
+		{name: "allowed still rejects empty key", allowEmpty: true, key: "", value: []byte{}, wantErr: ErrInvalidKey},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			called := false
+			mock, err := hostmock.New(hostmock.Config{
+				ExpectedCapability: "kvstore",
+				ExpectedFunction:   "set",
+				PayloadValidator: func(payload []byte) error {
+					called = true
+					var req proto.KVStoreSet
+					if err := req.UnmarshalVT(payload); err != nil {
+						return err
+					}
+					if !bytes.Equal(req.GetData(), tc.value) {
+						return fmt.Errorf("unexpected data: %q", req.GetData())
+					}
+					return nil
+				},
+				Response: func() []byte {
+					b, _ := (&proto.KVStoreSetResponse{Status: &sdkproto.Status{Code: 200}}).MarshalVT()
+					return b
+				},
+			})
+			if err != nil {
+				t.Fatalf("failed to create host mock: %v", err)
+			}
+
+			client, err := New(Config{HostCall: mock.HostCall, AllowEmptyValues: tc.allowEmpty})
+			if err != nil {
+				t.Fatalf("New returned error: %v", err)
+			}
+
+			if setErr := client.Set(tc.key, tc.value); !errors.Is(setErr, tc.wantErr) {
+				t.Fatalf("unexpected error: want %v got %v", tc.wantErr, setErr)
+			}
+			if called != tc.wantCalled {
+				t.Fatalf("host called: want %t got %t", tc.wantCalled, called)
+			}
+		})
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	t.Parallel()
+
+	release := make(chan struct{})
+	t.Cleanup(func() { close(release) })
+
+	blocking := func(string, string, string, []byte) ([]byte, error) {
+		<-release
+		return nil, nil
+	}
+
+	s, err := sdk.New(sdk.Config{
+		Handler:        func(b []byte) ([]byte, error) { return b, nil },
+		DefaultTimeout: 10 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("sdk.New returned error: %v", err)
+	}
+
+	t.Run("inherits SDK default", func(t *testing.T) {
+		t.Parallel()
+
+		client, err := New(Config{SDKConfig: s.Config(), HostCall: blocking})
+		if err != nil {
+			t.Fatalf("New returned error: %v", err)
+		}
+		if _, err := client.Get("key"); !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("expected %v, got %v", context.DeadlineExceeded, err)
+		}
+	})
+
+	t.Run("client override wins", func(t *testing.T) {
+		t.Parallel()
+
+		runtime := s.Config()
+		runtime.DefaultTimeout = time.Hour
+
+		client, err := New(Config{SDKConfig: runtime, HostCall: blocking, Timeout: 10 * time.Millisecond})
+		if err != nil {
+			t.Fatalf("New returned error: %v", err)
+		}
+		if err := client.Set("key", []byte("value")); !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("expected %v, got %v", context.DeadlineExceeded, err)
+		}
+	})
+
+	t.Run("request context bounds calls", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		client, err := New(Config{SDKConfig: sdk.WithRequestContext(ctx, s.Config()), HostCall: blocking})
+		if err != nil {
+			t.Fatalf("New returned error: %v", err)
+		}
+		if _, err := client.Keys(); !errors.Is(err, context.Canceled) {
+			t.Fatalf("expected %v, got %v", context.Canceled, err)
+		}
+	})
+
+	t.Run("negative override disables default", func(t *testing.T) {
+		t.Parallel()
+
+		client, err := New(Config{
+			SDKConfig: s.Config(),
+			Timeout:   -1,
+			HostCall: func(string, string, string, []byte) ([]byte, error) {
+				time.Sleep(20 * time.Millisecond)
+				return (&proto.KVStoreDeleteResponse{Status: &sdkproto.Status{Code: 200}}).MarshalVT()
+			},
+		})
+		if err != nil {
+			t.Fatalf("New returned error: %v", err)
+		}
+		if err := client.Delete("key"); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	})
+}
+
+func TestContextCancellation(t *testing.T) {
+	t.Parallel()
+
+	ops := []struct {
+		name string
+		call func(Client, context.Context) error
+	}{
+		{
+			name: "get",
+			call: func(c Client, ctx context.Context) error {
+				_, err := c.GetContext(ctx, "key")
+				return err
+			},
+		},
+		{
+			name: "set",
+			call: func(c Client, ctx context.Context) error {
+				return c.SetContext(ctx, "key", []byte("value"))
+			},
+		},
+		{
+			name: "delete",
+			call: func(c Client, ctx context.Context) error {
+				return c.DeleteContext(ctx, "key")
+			},
+		},
+		{
+			name: "keys",
+			call: func(c Client, ctx context.Context) error {
+				_, err := c.KeysContext(ctx)
+				return err
+			},
+		},
+	}
+
+	for _, op := range ops {
+		t.Run(op.name, func(t *testing.T) {
+			t.Parallel()
+
+			block := make(chan struct{})
+			t.Cleanup(func() { close(block) })
+
+			mock, err := hostmock.New(hostmock.Config{Block: block})
+			if err != nil {
+				t.Fatalf("failed to create hostmock: %v", err)
+			}
+
+			client, err := New(Config{HostCall: mock.HostCall})
+			if err != nil {
+				t.Fatalf("New returned error: %v", err)
+			}
+
+			t.Run("cancelled before call", func(t *testing.T) {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+
+				err := op.call(client, ctx)
+				if !errors.Is(err, context.Canceled) || errors.Is(err, sdk.ErrHostCall) {
+					t.Fatalf("expected %v without %v, got %v", context.Canceled, sdk.ErrHostCall, err)
+				}
+				if got := mock.Count(); got != 0 {
+					t.Fatalf("expected no host calls, got %d", got)
+				}
+			})
+
+			t.Run("deadline while blocked", func(t *testing.T) {
+				ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+				defer cancel()
+
+				err := op.call(client, ctx)
+				if !errors.Is(err, context.DeadlineExceeded) || errors.Is(err, sdk.ErrHostCall) {
+					t.Fatalf("expected %v without %v, got %v", context.DeadlineExceeded, sdk.ErrHostCall, err)
+				}
+			})
+		})
+	}
+}
+
+func TestGetMissThenHit(t *testing.T) {
+	t.Parallel()
+
+	getResponse := func(code int32, data []byte) func() ([]byte, error) {
+		return func() ([]byte, error) {
+			return (&proto.KVStoreGetResponse{Status: &sdkproto.Status{Code: code}, Data: data}).MarshalVT()
+		}
+	}
+
+	mock, err := hostmock.New(hostmock.Config{
+		ExpectedCapability: "kvstore",
+		ExpectedFunction:   "get",
+		Responses:          []func() ([]byte, error){getResponse(404, nil), getResponse(200, []byte("value"))},
+	})
+	if err != nil {
+		t.Fatalf("hostmock.New returned error: %v", err)
+	}
+
+	client, err := New(Config{HostCall: mock.HostCall})
+	if err != nil {
+		t.Fatalf("New returned error: %v", err)
+	}
+
+	if _, err := client.Get("key"); !errors.Is(err, ErrKeyNotFound) {
+		t.Fatalf("first Get: expected %v, got %v", ErrKeyNotFound, err)
+	}
+
+	got, err := client.Get("key")
+	if err != nil {
+		t.Fatalf("second Get returned error: %v", err)
+	}
+	if !bytes.Equal(got, []byte("value")) {
+		t.Fatalf("second Get: expected %q, got %q", "value", got)
+	}
+}
+
+func TestLookup(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		name      string
+		key       string
+		response  []byte
+		wantValue []byte
+		wantFound bool
+		wantErr   error
+	}{
+		{
+			name:      "Found",
+			key:       "key",
+			response:  kvtest.GetResponse(sdktest.OK(), []byte("value")),
+			wantValue: []byte("value"),
+			wantFound: true,
+		},
+		{
+			name:     "Not found",
+			key:      "key",
+			response: kvtest.GetResponse(sdktest.Failed(sdk.StatusNotFound, "missing"), nil),
+		},
+		{
+			name:     "Host error",
+			key:      "key",
+			response: kvtest.GetResponse(sdktest.Failed(sdk.StatusError, "boom"), nil),
+			wantErr:  sdk.ErrHostError,
+		},
+		{
+			name:    "Invalid key",
+			key:     "",
+			wantErr: ErrInvalidKey,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			hostCall := hostmock.Unexpected(t)
+			if tc.response != nil {
+				mock, err := hostmock.New(hostmock.Config{
+					ExpectedFunction: "get",
+					Response:         func() []byte { return tc.response },
+				})
+				if err != nil {
+					t.Fatalf("hostmock.New returned error: %v", err)
+				}
+				hostCall = mock.HostCall
+			}
+
+			client, err := New(Config{HostCall: hostCall})
+			if err != nil {
+				t.Fatalf("New returned error: %v", err)
+			}
+
+			value, found, err := client.Lookup(tc.key)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("expected error %v, got %v", tc.wantErr, err)
+			}
+			if found != tc.wantFound {
+				t.Fatalf("expected found %t, got %t", tc.wantFound, found)
+			}
+			if !bytes.Equal(value, tc.wantValue) {
+				t.Fatalf("expected value %q, got %q", tc.wantValue, value)
+			}
+		})
+	}
+}
+
+func TestHostStatusCode(t *testing.T) {
+	t.Parallel()
+
+	hostErr := errors.New("host failure")
+	mock, err := hostmock.New(hostmock.Config{
+		Fail:  true,
+		Error: hostErr,
+		Response: func() []byte {
+			b, _ := (&proto.KVStoreSetResponse{Status: &sdkproto.Status{Status: "disk full", Code: 500}}).MarshalVT()
+			return b
+		},
+	})
+	if err != nil {
+		t.Fatalf("hostmock.New returned error: %v", err)
+	}
+
+	client, err := New(Config{HostCall: mock.HostCall})
+	if err != nil {
+		t.Fatalf("New returned error: %v", err)
+	}
+
+	err = client.Set("key", []byte("value"))
+	for _, want := range []error{sdk.ErrHostError, hostErr} {
+		if !errors.Is(err, want) {
+			t.Fatalf("expected error to match %v, got %v", want, err)
+		}
+	}
+
+	var statusErr *sdk.HostStatusError
+	if !errors.As(err, &statusErr) {
+		t.Fatalf("expected *sdk.HostStatusError, got %T", err)
+	}
+	if statusErr.Code != 500 || statusErr.Message != "disk full" || statusErr.Operation != "set" {
+		t.Fatalf("unexpected status error: %+v", statusErr)
+	}
+}
+
+func TestHostStatusMessage(t *testing.T) {
+	t.Parallel()
+
+	failed := sdktest.Failed(sdk.StatusError, "replica unavailable")
+
+	tt := []struct {
+		name     string
+		response []byte
+		call     func(Client) error
+	}{
+		{
+			name:     "Get",
+			response: kvtest.GetResponse(failed, nil),
+			call:     func(c Client) error { _, err := c.Get("key"); return err },
+		},
+		{
+			name:     "Set",
+			response: kvtest.SetResponse(failed),
+			call:     func(c Client) error { return c.Set("key", []byte("value")) },
+		},
+		{
+			name:     "Delete",
+			response: kvtest.DeleteResponse(failed),
+			call:     func(c Client) error { return c.Delete("key") },
+		},
+		{
+			name:     "Keys",
+			response: kvtest.KeysResponse(failed),
+			call:     func(c Client) error { _, err := c.Keys(); return err },
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			mock, err := hostmock.New(hostmock.Config{Response: func() []byte { return tc.response }})
+			if err != nil {
+				t.Fatalf("hostmock.New returned error: %v", err)
+			}
+
+			client, err := New(Config{HostCall: mock.HostCall})
+			if err != nil {
+				t.Fatalf("New returned error: %v", err)
+			}
+
+			err = tc.call(client)
+			if !errors.Is(err, sdk.ErrHostError) {
+				t.Fatalf("expected %v, got %v", sdk.ErrHostError, err)
+			}
+			if !strings.Contains(err.Error(), "replica unavailable") {
+				t.Fatalf("expected host status message in %q", err.Error())
+			}
+		})
+	}
+}
+
+func TestStatusMapping(t *testing.T) {
+	t.Parallel()
+
+	ops := []struct {
+		name     string
+		response func(*sdkproto.Status) []byte
+		call     func(Client) error
+		notFound error
+	}{
+		{
+			name:     "Get",
+			response: func(s *sdkproto.Status) []byte { return kvtest.GetResponse(s, []byte("value")) },
+			call:     func(c Client) error { _, err := c.Get("key"); return err },
+			notFound: ErrKeyNotFound,
+		},
+		{
+			name:     "Set",
+			response: kvtest.SetResponse,
+			call:     func(c Client) error { return c.Set("key", []byte("value")) },
+			notFound: sdk.ErrHostError,
+		},
+		{
+			name:     "Delete",
+			response: kvtest.DeleteResponse,
+			call:     func(c Client) error { return c.Delete("key") },
+		},
+		{
+			name:     "Keys",
+			response: func(s *sdkproto.Status) []byte { return kvtest.KeysResponse(s, "key") },
+			call:     func(c Client) error { _, err := c.Keys(); return err },
+			notFound: sdk.ErrHostError,
+		},
+	}
+
+	for _, op := range ops {
+		tt := []struct {
+			name    string
+			status  *sdkproto.Status
+			wantErr error
+		}{
+			{name: "ok", status: sdktest.OK()},
+			{name: "partial", status: sdktest.Partial("degraded")},
+			{name: "bad input", status: sdktest.Failed(sdk.StatusBadInput, "bad"), wantErr: sdk.ErrHostError},
+			{name: "not found", status: sdktest.Failed(sdk.StatusNotFound, "missing"), wantErr: op.notFound},
+			{name: "unknown code", status: sdktest.Failed(299, "odd"), wantErr: sdk.ErrHostResponseInvalid},
+		}
+
+		for _, tc := range tt {
+			t.Run(op.name+"/"+tc.name, func(t *testing.T) {
+				t.Parallel()
+
+				response := op.response(tc.status)
+				mock, err := hostmock.New(hostmock.Config{Response: func() []byte { return response }})
+				if err != nil {
+					t.Fatalf("hostmock.New returned error: %v", err)
+				}
+
+				client, err := New(Config{HostCall: mock.HostCall})
+				if err != nil {
+					t.Fatalf("New returned error: %v", err)
+				}
+
+				err = op.call(client)
+				if tc.wantErr == nil {
+					if err != nil {
+						t.Fatalf("unexpected error: %v", err)
+					}
+					return
+				}
+				if !errors.Is(err, tc.wantErr) {
+					t.Fatalf("expected %v, got %v", tc.wantErr, err)
+				}
+			})
+		}
+	}
+}
+
+func TestObserver(t *testing.T) {
+	t.Parallel()
+
+	var events []sdk.HostCallEvent
+	store := map[string][]byte{"key": []byte("value")}
+	client := newStoreClient(t, store)
+	client.runtime.Observer = func(e sdk.HostCallEvent) { events = append(events, e) }
+	client.runtime.RequestID = "req-123"
+
+	if _, err := client.Get("key"); err != nil {
+		t.Fatalf("Get returned error: %v", err)
+	}
+
+	if len(events) != 1 {
+		t.Fatalf("expected one observed call, got %d", len(events))
+	}
+	e := events[0]
+	if e.Capability != "kvstore" || e.Function != "get" || e.Err != nil || e.RequestID != "req-123" {
+		t.Fatalf("unexpected event: %+v", e)
+	}
+
+	var req proto.KVStoreGet
+	if err := req.UnmarshalVT(e.Request); err != nil || req.GetKey() != "key" {
+		t.Fatalf("unexpected request payload: %v, %v", req.GetKey(), err)
+	}
+	var resp proto.KVStoreGetResponse
+	if err := resp.UnmarshalVT(e.Response); err != nil || string(resp.GetData()) != "value" {
+		t.Fatalf("unexpected response payload: %q, %v", resp.GetData(), err)
+	}
+}
+
+func TestPing(t *testing.T) {
+	t.Parallel()
+
+	status := func(code int32, message string) func() []byte {
+		return func() []byte {
+			b, _ := (&sdkproto.Status{Code: code, Status: message}).MarshalVT()
+			return b
+		}
+	}
+
+	tt := []struct {
+		name    string
+		cfg     hostmock.Config
+		wantErr error
+	}{
+		{
+			name:    "healthy",
+			cfg:     hostmock.Config{Response: status(200, "OK")},
+			wantErr: nil,
+		},
+		{
+			name:    "unhealthy status",
+			cfg:     hostmock.Config{Response: status(500, "unavailable")},
+			wantErr: sdk.ErrHostError,
+		},
+		{
+			name:    "host call failure",
+			cfg:     hostmock.Config{Fail: true, Error: errors.New("no such function")},
+			wantErr: sdk.ErrHostCall,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			tc.cfg.ExpectedCapability = "kvstore"
+			tc.cfg.ExpectedFunction = sdk.PingFunction
+			tc.cfg.PayloadValidator = func(payload []byte) error {
+				if len(payload) != 0 {
+					return fmt.Errorf("expected empty payload, got %q", payload)
+				}
+				return nil
+			}
+			mock, err := hostmock.New(tc.cfg)
+			if err != nil {
+				t.Fatalf("failed to create hostmock: %v", err)
+			}
+
+			client, err := New(Config{HostCall: mock.HostCall})
+			if err != nil {
+				t.Fatalf("New returned error: %v", err)
+			}
+
+			if err := client.Ping(); !errors.Is(err, tc.wantErr) {
+				t.Fatalf("expected %v, got %v", tc.wantErr, err)
+			}
+			if got := mock.Count(); got != 1 {
+				t.Fatalf("expected one host call, got %d", got)
+			}
+		})
+	}
+}
+
+func TestCapabilityOverride(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		name       string
+		capability string
+		want       string
+	}{
+		{name: "default", want: capabilityName},
+		{name: "custom", capability: "custom-kvstore", want: "custom-kvstore"},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			mock, err := hostmock.New(hostmock.Config{
+				ExpectedCapability: tc.want,
+				Response: func() []byte {
+					b, _ := (&sdkproto.Status{Code: 200}).MarshalVT()
+					return b
+				},
+			})
+			if err != nil {
+				t.Fatalf("failed to create hostmock: %v", err)
+			}
+
+			client, err := New(Config{HostCall: mock.HostCall, Capability: tc.capability})
+			if err != nil {
+				t.Fatalf("New returned error: %v", err)
+			}
+			if err := client.Ping(); err != nil {
+				t.Fatalf("Ping returned error: %v", err)
+			}
+
+			calls := mock.Calls()
+			if len(calls) != 1 || calls[0].Capability != tc.want {
+				t.Fatalf("expected one call to capability %q, got %+v", tc.want, calls)
+			}
+		})
+	}
+}
+
+func TestSetIfChanged(t *testing.T) {
+	t.Parallel()
+
+	if got, want := Hash([]byte("value")), Hash([]byte("value")); got != want || len(got) != 64 {
+		t.Fatalf("expected a stable 64-character hash, got %q and %q", got, want)
+	}
+	if Hash([]byte("value")) == Hash([]byte("other")) {
+		t.Fatalf("expected different values to hash differently")
+	}
+
+	tt := []struct {
+		name      string
+		store     map[string][]byte
+		value     []byte
+		wantWrite bool
+	}{
+		{name: "unchanged skips write", store: map[string][]byte{"key": []byte("value")}, value: []byte("value")},
+		{
+			name:      "changed writes",
+			store:     map[string][]byte{"key": []byte("old")},
+			value:     []byte("value"),
+			wantWrite: true,
+		},
+		{name: "missing writes", store: map[string][]byte{}, value: []byte("value"), wantWrite: true},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := newStoreClient(t, tc.store)
+			var sets int
+			hostCall := client.hostCall
+			client.hostCall = func(namespace, capability, function string, payload []byte) ([]byte, error) {
+				if function == "set" {
+					sets++
+				}
+				return hostCall(namespace, capability, function, payload)
+			}
+
+			wrote, err := client.SetIfChanged("key", tc.value)
+			if err != nil {
+				t.Fatalf("SetIfChanged returned error: %v", err)
+			}
+			if wrote != tc.wantWrite || (sets == 1) != tc.wantWrite {
+				t.Fatalf("expected write %t, got write %t with %d set calls", tc.wantWrite, wrote, sets)
+			}
+			if !bytes.Equal(tc.store["key"], tc.value) {
+				t.Fatalf("expected stored value %q, got %q", tc.value, tc.store["key"])
+			}
+		})
+	}
+
+	t.Run("get failure skips write", func(t *testing.T) {
+		t.Parallel()
+
+		mock, err := hostmock.New(hostmock.Config{Fail: true, Error: errors.New("host failure")})
+		if err != nil {
+			t.Fatalf("failed to create hostmock: %v", err)
+		}
+		client, err := New(Config{HostCall: mock.HostCall})
+		if err != nil {
+			t.Fatalf("New returned error: %v", err)
+		}
+
+		wrote, err := client.SetIfChanged("key", []byte("value"))
+		if wrote || !errors.Is(err, sdk.ErrHostCall) {
+			t.Fatalf("expected no write and %v, got %t, %v", sdk.ErrHostCall, wrote, err)
+		}
+		if mock.Count() != 1 {
+			t.Fatalf("expected only the get call, got %d calls", mock.Count())
+		}
+	})
+}
+
+func TestClose(t *testing.T) {
+	t.Parallel()
+
+	mock, err := hostmock.New(hostmock.Config{})
+	if err != nil {
+		t.Fatalf("failed to create hostmock: %v", err)
+	}
+
+	var client Client
+	client, err = New(Config{HostCall: mock.HostCall})
+	if err != nil {
+		t.Fatalf("New returned error: %v", err)
+	}
+
+	// Close must be safe to defer unconditionally and to call more than once.
+	for i := range 2 {
+		if err := client.Close(); err != nil {
+			t.Fatalf("Close call %d returned error: %v", i+1, err)
+		}
+	}
+	if mock.Count() != 0 {
+		t.Fatalf("expected Close to make no host calls, got %d", mock.Count())
+	}
+}
+
+func TestZeroValueClient(t *testing.T) {
+	t.Parallel()
+
+	// A client not built with New has no host call; it must fail rather than panic.
+	var client StoreClient
+	if _, err := client.Get("key"); !errors.Is(err, sdk.ErrNilHostCall) || !errors.Is(err, sdk.ErrHostCall) {
+		t.Fatalf("expected ErrNilHostCall wrapped in ErrHostCall, got %v", err)
+	}
+}
+
+func TestEmptyHostResponse(t *testing.T) {
+	t.Parallel()
+
+	ops := []struct {
+		name string
+		call func(*StoreClient) error
+	}{
+		{name: "get", call: func(c *StoreClient) error { _, err := c.Get("key"); return err }},
+		{name: "set", call: func(c *StoreClient) error { return c.Set("key", []byte("v")) }},
+		{name: "delete", call: func(c *StoreClient) error { return c.Delete("key") }},
+		{name: "keys", call: func(c *StoreClient) error { _, err := c.Keys(); return err }},
+		{name: "ping", call: func(c *StoreClient) error { return c.Ping() }},
+	}
+
+	payloads := map[string][]byte{"nil": nil, "empty": {}}
+
+	for _, op := range ops {
+		for kind, payload := range payloads {
+			t.Run(op.name+" "+kind, func(t *testing.T) {
+				t.Parallel()
+
+				mock, err := hostmock.New(hostmock.Config{Response: func() []byte { return payload }})
+				if err != nil {
+					t.Fatalf("failed to create hostmock: %v", err)
+				}
+
+				client, err := New(Config{HostCall: mock.HostCall})
+				if err != nil {
+					t.Fatalf("New returned error: %v", err)
+				}
+
+				if err := op.call(client); !errors.Is(err, sdk.ErrHostResponseInvalid) {
+					t.Fatalf("expected %v, got %v", sdk.ErrHostResponseInvalid, err)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,43 @@
+/*
+Package kvtest builds canned kvstore host responses for tests.
+
+Each helper returns the encoded payload the kv client expects from the host,
+ready to return from a hostmock Response or Responses entry or a hand-written
+host call. Every helper takes the status the response carries, built with
+sdktest.OK, sdktest.Partial, or sdktest.Failed, so one helper covers success
+and failure alike. Bare Ping statuses come from sdktest.StatusResponse:
+
+	mock, _ := hostmock.New(hostmock.Config{
+	  Response: func() []byte { return kvtest.GetResponse(sdktest.OK(), []byte("value")) },
+	})
+*/
+package kvtest
+
+import (
+	sdkproto "github.com/tarmac-project/protobuf-go/sdk"
+	kvstore "github.com/tarmac-project/protobuf-go/sdk/kvstore"
+)
+
+// GetResponse encodes a get response carrying data.
+func GetResponse(status *sdkproto.Status, data []byte) []byte {
+	b, _ := (&kvstore.KVStoreGetResponse{Status: status, Data: data}).MarshalVT()
+	return b
+}
+
+// SetResponse encodes a set response.
+func SetResponse(status *sdkproto.Status) []byte {
+	b, _ := (&kvstore.KVStoreSetResponse{Status: status}).MarshalVT()
+	return b
+}
+
+// DeleteResponse encodes a delete response.
+func DeleteResponse(status *sdkproto.Status) []byte {
+	b, _ := (&kvstore.KVStoreDeleteResponse{Status: status}).MarshalVT()
+	return b
+}
+
+// KeysResponse encodes a keys response listing keys in the given order.
+func KeysResponse(status *sdkproto.Status, keys ...string) []byte {
+	b, _ := (&kvstore.KVStoreKeysResponse{Status: status, Keys: keys}).MarshalVT()
+	return b
+}
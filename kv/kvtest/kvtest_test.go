@@ -0,0 +1,66 @@
+package kvtest_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	sdk "github.com/tarmac-project/sdk"
+	"github.com/tarmac-project/sdk/hostmock"
+	"github.com/tarmac-project/sdk/kv"
+	"github.com/tarmac-project/sdk/kv/kvtest"
+	"github.com/tarmac-project/sdk/sdktest"
+)
+
+func TestResponses(t *testing.T) {
+	t.Parallel()
+
+	mock, err := hostmock.New(hostmock.Config{
+		Functions: map[string]hostmock.FunctionConfig{
+			"get": {Responses: []func() ([]byte, error){
+				func() ([]byte, error) { return kvtest.GetResponse(sdktest.OK(), []byte("value")), nil },
+				func() ([]byte, error) {
+					return kvtest.GetResponse(sdktest.Failed(sdk.StatusNotFound, "missing"), nil), nil
+				},
+			}},
+			"set": {Response: func() []byte { return kvtest.SetResponse(sdktest.OK()) }},
+			"delete": {
+				Response: func() []byte { return kvtest.DeleteResponse(sdktest.Failed(sdk.StatusError, "boom")) },
+			},
+			"keys": {Response: func() []byte { return kvtest.KeysResponse(sdktest.OK(), "b", "a") }},
+			sdk.PingFunction: {
+				Response: func() []byte { return sdktest.StatusResponse(sdktest.OK()) },
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to create hostmock: %v", err)
+	}
+
+	client, err := kv.New(kv.Config{HostCall: mock.HostCall})
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	if got, err := client.Get("key"); err != nil || string(got) != "value" {
+		t.Fatalf("Get: got %q, %v", got, err)
+	}
+	if _, err := client.Get("key"); !errors.Is(err, kv.ErrKeyNotFound) {
+		t.Fatalf("Get miss: expected %v, got %v", kv.ErrKeyNotFound, err)
+	}
+	if err := client.Set("key", []byte("value")); err != nil {
+		t.Fatalf("Set returned error: %v", err)
+	}
+
+	var statusErr *sdk.HostStatusError
+	if err := client.Delete("key"); !errors.As(err, &statusErr) || statusErr.Code != sdk.StatusError {
+		t.Fatalf("Delete: expected host status %d, got %v", sdk.StatusError, err)
+	}
+
+	if keys, err := client.Keys(); err != nil || !slices.Equal(keys, []string{"b", "a"}) {
+		t.Fatalf("Keys: got %q, %v", keys, err)
+	}
+	if err := client.Ping(); err != nil {
+		t.Fatalf("Ping returned error: %v", err)
+	}
+}
@@ -3,7 +3,18 @@ Package logging offers a client for emitting log entries from Tarmac WebAssembly
 functions to the host runtime.
 
 The package exposes a small interface with convenience methods for common log
-levels (Info, Warn, Error, Debug, Trace). A client instance handles the host
-interaction behind the scenes, so guest code can focus on writing logs.
+levels (Info, Warn, Error, Debug, Trace) and formatted variants (Infof, Warnf,
+Errorf, Debugf, Tracef) that apply fmt.Sprintf before sending. LogAndError and
+Fatalf log an entry and return an error wrapping ErrFatal for the handler to
+propagate; nothing exits the WebAssembly module. LogError sends a message and
+an error, including its wrapped chain, as a JSON object. A client instance handles the
+host interaction behind the scenes, so guest code can focus on writing logs.
+Config.Sampler caps log volume by dropping entries before the host call; EveryN
+keeps one in every n entries per level.
+NewWriter adapts a Client to io.Writer, logging one entry per line, so libraries
+that write to an io.Writer can target the host.
+
+This is the SDK's only logging package; every method forwards to the host's
+logging capability.
 */
 package logging
@@ -1,12 +1,33 @@
 package logging
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
 	sdk "github.com/tarmac-project/sdk"
 	wapc "github.com/wapc/wapc-guest-tinygo"
 )
 
+// capabilityName is the default host capability name for log entries.
 const capabilityName = "logging"
 
+// Level names a log level; its value is the host function used for the entry.
+type Level string
+
+// Log levels accepted by LogAndError.
+const (
+	LevelInfo  Level = "Info"
+	LevelWarn  Level = "Warn"
+	LevelError Level = "Error"
+	LevelDebug Level = "Debug"
+	LevelTrace Level = "Trace"
+)
+
+// ErrFatal is returned by LogAndError and Fatalf so handlers can propagate a
+// terminating failure after it has been logged.
+var ErrFatal = errors.New("fatal error")
+
 // Client exposes convenience helpers for sending log entries to the host runtime.
 type Client interface {
 	Info(message string)
@@ -14,6 +35,18 @@ type Client interface {
 	Error(message string)
 	Debug(message string)
 	Trace(message string)
+
+	Infof(format string, args ...any)
+	Warnf(format string, args ...any)
+	Errorf(format string, args ...any)
+	Debugf(format string, args ...any)
+	Tracef(format string, args ...any)
+
+	LogAndError(level Level, message string) error
+	LogError(message string, err error)
+	Fatalf(format string, args ...any) error
+
+	Close() error
 }
 
 // Config controls how a Client instance interacts with the host runtime.
@@ -23,12 +56,30 @@ type Config struct {
 
 	// HostCall overrides the waPC host function used for logging operations.
 	HostCall func(string, string, string, []byte) ([]byte, error)
+
+	// Capability overrides the host capability name used for host calls, for
+	// hosts that register the capability under a custom name. When empty,
+	// "logging" is used.
+	Capability string
+
+	// Sampler, when set, limits log volume by dropping entries it rejects
+	// before they reach the host. See EveryN.
+	Sampler Sampler
 }
 
 // HostLogger implements Client using the configured host call entrypoint.
 type HostLogger struct {
-	runtime  sdk.RuntimeConfig
-	hostCall func(string, string, string, []byte) ([]byte, error)
+	runtime    sdk.RuntimeConfig
+	hostCall   func(string, string, string, []byte) ([]byte, error)
+	capability string
+	sampler    Sampler
+}
+
+// errorEntry is the JSON payload sent by LogError.
+type errorEntry struct {
+	Message string   `json:"message"`
+	Error   string   `json:"error,omitempty"`
+	Chain   []string `json:"chain,omitempty"`
 }
 
 // Ensure client implements the Client interface at compile time.
@@ -46,9 +97,16 @@ func New(cfg Config) (*HostLogger, error) {
 		hostCall = wapc.HostCall
 	}
 
+	capability := cfg.Capability
+	if capability == "" {
+		capability = capabilityName
+	}
+
 	return &HostLogger{
-		runtime:  runtimeCfg,
-		hostCall: hostCall,
+		runtime:    runtimeCfg,
+		hostCall:   hostCall,
+		capability: capability,
+		sampler:    cfg.Sampler,
 	}, nil
 }
 
@@ -58,6 +116,94 @@ func (c *HostLogger) Error(message string) { c.log("Error", message) }
 func (c *HostLogger) Debug(message string) { c.log("Debug", message) }
 func (c *HostLogger) Trace(message string) { c.log("Trace", message) }
 
+// Infof formats according to format and args and logs the result at Info level.
+func (c *HostLogger) Infof(format string, args ...any) { c.log("Info", fmt.Sprintf(format, args...)) }
+
+// Warnf formats according to format and args and logs the result at Warn level.
+func (c *HostLogger) Warnf(format string, args ...any) { c.log("Warn", fmt.Sprintf(format, args...)) }
+
+// Errorf formats according to format and args and logs the result at Error level.
+func (c *HostLogger) Errorf(format string, args ...any) { c.log("Error", fmt.Sprintf(format, args...)) }
+
+// Debugf formats according to format and args and logs the result at Debug level.
+func (c *HostLogger) Debugf(format string, args ...any) { c.log("Debug", fmt.Sprintf(format, args...)) }
+
+// Tracef formats according to format and args and logs the result at Trace level.
+func (c *HostLogger) Tracef(format string, args ...any) { c.log("Trace", fmt.Sprintf(format, args...)) }
+
+// LogAndError logs message at level and returns an error wrapping ErrFatal with
+// the same message. Unknown levels are logged at Error. It never exits the
+// process, so the caller must return the error from its handler.
+func (c *HostLogger) LogAndError(level Level, message string) error {
+	switch level {
+	case LevelInfo, LevelWarn, LevelError, LevelDebug, LevelTrace:
+	default:
+		level = LevelError
+	}
+
+	c.log(string(level), message)
+
+	return fmt.Errorf("%w: %s", ErrFatal, message)
+}
+
+// Close releases resources associated with the client. It is a no-op.
+func (c *HostLogger) Close() error {
+	return nil
+}
+
+// LogError logs message and err at Error level as a JSON object with message,
+// error, and chain fields. The chain lists the messages of the errors wrapped
+// by err, depth first, so the host keeps the structure that fmt.Sprintf would
+// flatten.
+func (c *HostLogger) LogError(message string, err error) {
+	entry := errorEntry{Message: message}
+	if err != nil {
+		entry.Error = err.Error()
+		entry.Chain = unwrapChain(err)
+	}
+
+	payload, marshalErr := json.Marshal(entry)
+	if marshalErr != nil {
+		return
+	}
+
+	c.log(string(LevelError), string(payload))
+}
+
+// unwrapChain returns the messages of the errors wrapped by err, depth first,
+// following both single and joined wrapping.
+func unwrapChain(err error) []string {
+	var children []error
+	switch e := err.(type) {
+	case interface{ Unwrap() error }:
+		if next := e.Unwrap(); next != nil {
+			children = []error{next}
+		}
+	case interface{ Unwrap() []error }:
+		children = e.Unwrap()
+	}
+
+	var chain []string
+	for _, child := range children {
+		if child == nil {
+			continue
+		}
+		chain = append(chain, child.Error())
+		chain = append(chain, unwrapChain(child)...)
+	}
+
+	return chain
+}
+
+// Fatalf formats according to format and args, logs the result at Error level,
+// and returns an error wrapping ErrFatal for the handler to propagate.
+func (c *HostLogger) Fatalf(format string, args ...any) error {
+	return c.LogAndError(LevelError, fmt.Sprintf(format, args...))
+}
+
 func (c *HostLogger) log(fn string, message string) {
-	_, _ = c.hostCall(c.runtime.Namespace, capabilityName, fn, []byte(message))
+	if c.sampler != nil && !c.sampler.Sample(Level(fn)) {
+		return
+	}
+	_, _ = c.runtime.Call(c.hostCall, 0, c.capability, fn, []byte(message))
 }
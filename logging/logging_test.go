@@ -1,7 +1,12 @@
 package logging
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
 	"reflect"
+	"slices"
+	"strings"
 	"testing"
 
 	sdk "github.com/tarmac-project/sdk"
@@ -107,3 +112,414 @@ func TestClientLogMethods(t *testing.T) {
 		})
 	}
 }
+
+func TestClientFormatMethods(t *testing.T) {
+	t.Parallel()
+
+	const want = "processed 3 items for \"tenant-a\" in 1.50s"
+
+	tt := []struct {
+		name   string
+		fn     string
+		invoke func(Client)
+	}{
+		{"Infof", "Info", func(c Client) { c.Infof("processed %d items for %q in %.2fs", 3, "tenant-a", 1.5) }},
+		{"Warnf", "Warn", func(c Client) { c.Warnf("processed %d items for %q in %.2fs", 3, "tenant-a", 1.5) }},
+		{"Errorf", "Error", func(c Client) { c.Errorf("processed %d items for %q in %.2fs", 3, "tenant-a", 1.5) }},
+		{"Debugf", "Debug", func(c Client) { c.Debugf("processed %d items for %q in %.2fs", 3, "tenant-a", 1.5) }},
+		{"Tracef", "Trace", func(c Client) { c.Tracef("processed %d items for %q in %.2fs", 3, "tenant-a", 1.5) }},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			mock, err := hostmock.New(hostmock.Config{
+				ExpectedCapability: capabilityName,
+				ExpectedFunction:   tc.fn,
+			})
+			if err != nil {
+				t.Fatalf("hostmock: %v", err)
+			}
+
+			cli, err := New(Config{HostCall: mock.HostCall})
+			if err != nil {
+				t.Fatalf("New returned error: %v", err)
+			}
+
+			tc.invoke(cli)
+
+			payloads := mock.Payloads()
+			if len(payloads) != 1 || string(payloads[0]) != want {
+				t.Fatalf("expected one payload %q, got %q", want, payloads)
+			}
+		})
+	}
+}
+
+func TestLogAndError(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		name    string
+		invoke  func(Client) error
+		wantFn  string
+		wantMsg string
+	}{
+		{
+			name:    "warn level",
+			invoke:  func(c Client) error { return c.LogAndError(LevelWarn, "quota exceeded") },
+			wantFn:  "Warn",
+			wantMsg: "quota exceeded",
+		},
+		{
+			name:    "unknown level logs at error",
+			invoke:  func(c Client) error { return c.LogAndError(Level("Critical"), "disk failure") },
+			wantFn:  "Error",
+			wantMsg: "disk failure",
+		},
+		{
+			name:    "fatalf formats at error",
+			invoke:  func(c Client) error { return c.Fatalf("cannot open %s: %d", "db", 3) },
+			wantFn:  "Error",
+			wantMsg: "cannot open db: 3",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			mock, err := hostmock.New(hostmock.Config{
+				ExpectedCapability: capabilityName,
+				ExpectedFunction:   tc.wantFn,
+			})
+			if err != nil {
+				t.Fatalf("hostmock: %v", err)
+			}
+
+			cli, err := New(Config{HostCall: mock.HostCall})
+			if err != nil {
+				t.Fatalf("New returned error: %v", err)
+			}
+
+			gotErr := tc.invoke(cli)
+			if !errors.Is(gotErr, ErrFatal) {
+				t.Fatalf("expected %v, got %v", ErrFatal, gotErr)
+			}
+			if !strings.Contains(gotErr.Error(), tc.wantMsg) {
+				t.Fatalf("expected error to contain %q, got %q", tc.wantMsg, gotErr)
+			}
+
+			calls := mock.Calls()
+			if len(calls) != 1 || calls[0].Function != tc.wantFn || string(calls[0].Payload) != tc.wantMsg {
+				t.Fatalf("expected one %s entry %q, got %+v", tc.wantFn, tc.wantMsg, calls)
+			}
+		})
+	}
+}
+
+func TestSampler(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		name      string
+		sampler   Sampler
+		wantDebug int
+		wantInfo  int
+	}{
+		{name: "one in ten debug", sampler: EveryN(10, LevelDebug), wantDebug: 10, wantInfo: 100},
+		{name: "one in ten all levels", sampler: EveryN(10), wantDebug: 10, wantInfo: 10},
+		{name: "n of one sends all", sampler: EveryN(1), wantDebug: 100, wantInfo: 100},
+		{name: "no sampler", wantDebug: 100, wantInfo: 100},
+		{name: "custom sampler", sampler: levelFilter(LevelInfo), wantDebug: 0, wantInfo: 100},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			mock, err := hostmock.New(hostmock.Config{ExpectedCapability: capabilityName})
+			if err != nil {
+				t.Fatalf("hostmock: %v", err)
+			}
+
+			cli, err := New(Config{HostCall: mock.HostCall, Sampler: tc.sampler})
+			if err != nil {
+				t.Fatalf("New returned error: %v", err)
+			}
+
+			for i := range 100 {
+				cli.Debugf("debug %d", i)
+				cli.Info("info")
+			}
+
+			counts := map[string]int{}
+			for _, call := range mock.Calls() {
+				counts[call.Function]++
+			}
+			if counts["Debug"] != tc.wantDebug || counts["Info"] != tc.wantInfo {
+				t.Fatalf("expected %d Debug and %d Info calls, got %v", tc.wantDebug, tc.wantInfo, counts)
+			}
+		})
+	}
+}
+
+// levelFilter is a Sampler that only sends entries at the given level.
+type levelFilter Level
+
+func (l levelFilter) Sample(level Level) bool { return level == Level(l) }
+
+func TestWriter(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		name   string
+		level  Level
+		writes []string
+		flush  bool
+		wantFn string
+		want   []string
+	}{
+		{
+			name:   "one call per line",
+			level:  LevelInfo,
+			writes: []string{"first\nsecond\nthird\n"},
+			wantFn: "Info",
+			want:   []string{"first", "second", "third"},
+		},
+		{
+			name:   "partial lines across writes",
+			level:  LevelWarn,
+			writes: []string{"par", "tial\nnext ", "line\r\ntrailing"},
+			wantFn: "Warn",
+			want:   []string{"partial", "next line"},
+		},
+		{
+			name:   "flush sends remainder",
+			level:  LevelDebug,
+			writes: []string{"done\nleft", "over"},
+			flush:  true,
+			wantFn: "Debug",
+			want:   []string{"done", "leftover"},
+		},
+		{
+			name:   "empty lines are sent",
+			level:  LevelTrace,
+			writes: []string{"\n\n"},
+			wantFn: "Trace",
+			want:   []string{"", ""},
+		},
+		{
+			name:   "unknown level logs at error",
+			level:  Level("Critical"),
+			writes: []string{"boom\n"},
+			wantFn: "Error",
+			want:   []string{"boom"},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			mock, err := hostmock.New(hostmock.Config{
+				ExpectedCapability: capabilityName,
+				ExpectedFunction:   tc.wantFn,
+			})
+			if err != nil {
+				t.Fatalf("hostmock: %v", err)
+			}
+
+			cli, err := New(Config{HostCall: mock.HostCall})
+			if err != nil {
+				t.Fatalf("New returned error: %v", err)
+			}
+
+			w := NewWriter(cli, tc.level)
+			for _, chunk := range tc.writes {
+				n, err := w.Write([]byte(chunk))
+				if err != nil || n != len(chunk) {
+					t.Fatalf("Write(%q) = %d, %v", chunk, n, err)
+				}
+			}
+			if tc.flush {
+				w.Flush()
+			}
+
+			var got []string
+			for _, call := range mock.Calls() {
+				if call.Function != tc.wantFn {
+					t.Fatalf("expected %s entries, got %s", tc.wantFn, call.Function)
+				}
+				got = append(got, string(call.Payload))
+			}
+			if !slices.Equal(got, tc.want) {
+				t.Fatalf("expected entries %q, got %q", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestLogError(t *testing.T) {
+	t.Parallel()
+
+	errRoot := errors.New("connection refused")
+	errOther := errors.New("retry budget exhausted")
+
+	tt := []struct {
+		name string
+		err  error
+		want errorEntry
+	}{
+		{
+			name: "wrapped error",
+			err:  fmt.Errorf("query users: %w", errRoot),
+			want: errorEntry{
+				Message: "request failed",
+				Error:   "query users: connection refused",
+				Chain:   []string{"connection refused"},
+			},
+		},
+		{
+			name: "joined errors",
+			err:  errors.Join(fmt.Errorf("dial: %w", errRoot), errOther),
+			want: errorEntry{
+				Message: "request failed",
+				Error:   "dial: connection refused\nretry budget exhausted",
+				Chain:   []string{"dial: connection refused", "connection refused", "retry budget exhausted"},
+			},
+		},
+		{
+			name: "nil error",
+			want: errorEntry{Message: "request failed"},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			mock, err := hostmock.New(hostmock.Config{
+				ExpectedCapability: capabilityName,
+				ExpectedFunction:   "Error",
+				PayloadValidator: func(payload []byte) error {
+					var got errorEntry
+					if err := json.Unmarshal(payload, &got); err != nil {
+						return fmt.Errorf("payload is not JSON: %w", err)
+					}
+					if !reflect.DeepEqual(got, tc.want) {
+						return fmt.Errorf("payload mismatch: want %+v, got %+v", tc.want, got)
+					}
+					return nil
+				},
+			})
+			if err != nil {
+				t.Fatalf("hostmock: %v", err)
+			}
+
+			cli, err := New(Config{HostCall: mock.HostCall})
+			if err != nil {
+				t.Fatalf("New returned error: %v", err)
+			}
+
+			var events []sdk.HostCallEvent
+			cli.runtime.Observer = func(e sdk.HostCallEvent) { events = append(events, e) }
+
+			cli.LogError("request failed", tc.err)
+
+			if len(events) != 1 || events[0].Err != nil {
+				t.Fatalf("expected one accepted host call, got %+v", events)
+			}
+		})
+	}
+}
+
+func TestObserver(t *testing.T) {
+	t.Parallel()
+
+	var events []sdk.HostCallEvent
+	cli, err := New(Config{
+		SDKConfig: sdk.RuntimeConfig{
+			Namespace: "observed",
+			Observer:  func(e sdk.HostCallEvent) { events = append(events, e) },
+		},
+		HostCall: func(string, string, string, []byte) ([]byte, error) { return nil, nil },
+	})
+	if err != nil {
+		t.Fatalf("New returned error: %v", err)
+	}
+
+	cli.Warn("disk almost full")
+
+	if len(events) != 1 {
+		t.Fatalf("expected one observed call, got %d", len(events))
+	}
+	e := events[0]
+	if e.Namespace != "observed" || e.Capability != capabilityName || e.Function != "Warn" {
+		t.Fatalf("unexpected routing in event: %+v", e)
+	}
+	if string(e.Request) != "disk almost full" {
+		t.Fatalf("unexpected request payload: %q", e.Request)
+	}
+}
+
+func TestCapabilityOverride(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		name       string
+		capability string
+		want       string
+	}{
+		{name: "default", want: capabilityName},
+		{name: "custom", capability: "custom-logging", want: "custom-logging"},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			mock, err := hostmock.New(hostmock.Config{ExpectedCapability: tc.want})
+			if err != nil {
+				t.Fatalf("failed to create hostmock: %v", err)
+			}
+
+			client, err := New(Config{HostCall: mock.HostCall, Capability: tc.capability})
+			if err != nil {
+				t.Fatalf("New returned error: %v", err)
+			}
+			client.Info("hello")
+
+			calls := mock.Calls()
+			if len(calls) != 1 || calls[0].Capability != tc.want {
+				t.Fatalf("expected one call to capability %q, got %+v", tc.want, calls)
+			}
+		})
+	}
+}
+
+func TestClose(t *testing.T) {
+	t.Parallel()
+
+	mock, err := hostmock.New(hostmock.Config{})
+	if err != nil {
+		t.Fatalf("failed to create hostmock: %v", err)
+	}
+
+	var client Client
+	client, err = New(Config{HostCall: mock.HostCall})
+	if err != nil {
+		t.Fatalf("New returned error: %v", err)
+	}
+
+	// Close must be safe to defer unconditionally and to call more than once.
+	for i := range 2 {
+		if err := client.Close(); err != nil {
+			t.Fatalf("Close call %d returned error: %v", i+1, err)
+		}
+	}
+	if mock.Count() != 0 {
+		t.Fatalf("expected Close to make no host calls, got %d", mock.Count())
+	}
+}
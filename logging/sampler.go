@@ -0,0 +1,45 @@
+package logging
+
+import (
+	"sync/atomic"
+)
+
+// Sampler decides whether a log entry at level is sent to the host. Entries it
+// rejects are dropped before the host call. Implementations must be safe for
+// concurrent use.
+type Sampler interface {
+	Sample(level Level) bool
+}
+
+// everyN samples one in every n entries for a set of levels.
+type everyN struct {
+	n      uint64
+	levels map[Level]*atomic.Uint64
+}
+
+// EveryN returns a Sampler that sends the first entry and then one in every n
+// entries for each of levels, counting each level separately. When no levels
+// are given every level is sampled; entries at other levels are always sent.
+// An n of one or less sends everything.
+func EveryN(n uint64, levels ...Level) Sampler {
+	if len(levels) == 0 {
+		levels = []Level{LevelInfo, LevelWarn, LevelError, LevelDebug, LevelTrace}
+	}
+
+	s := &everyN{n: n, levels: make(map[Level]*atomic.Uint64, len(levels))}
+	for _, level := range levels {
+		s.levels[level] = new(atomic.Uint64)
+	}
+
+	return s
+}
+
+// Sample reports whether the entry is one of every n for its level.
+func (s *everyN) Sample(level Level) bool {
+	counter, ok := s.levels[level]
+	if !ok || s.n <= 1 {
+		return true
+	}
+
+	return (counter.Add(1)-1)%s.n == 0
+}
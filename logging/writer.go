@@ -0,0 +1,81 @@
+package logging
+
+import (
+	"bytes"
+	"io"
+	"sync"
+)
+
+// Writer is an io.Writer that sends each newline-terminated line written to it
+// as a log entry at a fixed level. Partial lines are buffered until their
+// newline arrives or Flush is called. A Writer is safe for concurrent use.
+type Writer struct {
+	mu     sync.Mutex
+	client Client
+	level  Level
+	buf    []byte
+}
+
+// Ensure Writer satisfies io.Writer at compile time.
+var _ io.Writer = (*Writer)(nil)
+
+// NewWriter returns a Writer that logs lines through client at level. Unknown
+// levels are logged at Error. It lets libraries that write to an io.Writer,
+// such as a standard library logger, target the host.
+func NewWriter(client Client, level Level) *Writer {
+	return &Writer{client: client, level: level}
+}
+
+// Write logs every complete line in p, without its trailing newline or
+// carriage return, and buffers any remainder. It always reports len(p) bytes
+// written.
+func (w *Writer) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		w.emit(w.buf[:i])
+		w.buf = w.buf[i+1:]
+	}
+
+	// Reclaim the consumed prefix so the buffer does not grow without bound.
+	if len(w.buf) == 0 {
+		w.buf = nil
+	}
+
+	return len(p), nil
+}
+
+// Flush logs any buffered partial line.
+func (w *Writer) Flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if len(w.buf) > 0 {
+		w.emit(w.buf)
+		w.buf = nil
+	}
+}
+
+// emit sends line at the writer's level.
+func (w *Writer) emit(line []byte) {
+	message := string(bytes.TrimSuffix(line, []byte("\r")))
+
+	switch w.level {
+	case LevelInfo:
+		w.client.Info(message)
+	case LevelWarn:
+		w.client.Warn(message)
+	case LevelDebug:
+		w.client.Debug(message)
+	case LevelTrace:
+		w.client.Trace(message)
+	default:
+		w.client.Error(message)
+	}
+}
@@ -3,7 +3,24 @@ Package metrics provides a client for creating custom metrics through the
 Tarmac host runtime.
 
 The package exposes constructors for Counter, Gauge, and Histogram metric
-handles, each backed by protobuf payloads sent over waPC host calls.
+handles, each backed by protobuf payloads sent over waPC host calls. Set
+Config.MetricPrefix to namespace every metric name (joined with an underscore)
+and avoid collisions across functions.
+
+NewCounterVec, NewGaugeVec, and NewHistogramVec partition a metric by label
+values. The host protocol has no label field, so WithLabelValues returns a
+cached child whose name is the metric name followed by each label value, joined
+with underscores (e.g. requests_GET_200). Label values must therefore match the
+metric name format without underscores, so distinct values always produce
+distinct names, and the number of values must match the declared labels.
+
+NewNop returns a Client that never calls the host, for environments where
+metrics should be disabled. Its constructors validate names and labels like
+HostMetrics, and its handles, like the zero value of Counter, Gauge, and
+Histogram, discard every update without allocating.
+
+Code that depends on the Client interface rather than *HostMetrics can be
+tested with the metrics/mock package, whose Recorder captures every update.
 
 Metric emission methods intentionally follow Prometheus-style ergonomics:
 Inc/Dec/Observe are best-effort and do not return errors. Marshal or host-call
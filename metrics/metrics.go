@@ -10,18 +10,24 @@ import (
 )
 
 const (
-	capabilityName = "metrics"
+	capabilityName = "metrics" // default host capability name
 	fnCounter      = "counter"
 	fnGauge        = "gauge"
 	fnHistogram    = "histogram"
 	actionInc      = "inc"
 	actionDec      = "dec"
+
+	// prefixSeparator joins Config.MetricPrefix and caller-supplied metric names.
+	prefixSeparator = "_"
 )
 
 var (
 	// ErrInvalidMetricName indicates a metric name that does not match the supported format.
 	ErrInvalidMetricName = errors.New("metric name is invalid")
 
+	// ErrInvalidMetricPrefix indicates a metric prefix that does not match the supported format.
+	ErrInvalidMetricPrefix = errors.New("metric prefix is invalid")
+
 	// isMetricNameValid validates metric names using the same pattern as tarmac callback validation.
 	isMetricNameValid = regexp.MustCompile(`^[a-zA-Z0-9_:][a-zA-Z0-9_:]*$`)
 )
@@ -39,6 +45,18 @@ type Client interface {
 
 	// NewHistogram creates a named histogram metric handle.
 	NewHistogram(name string) (*Histogram, error)
+
+	// NewCounterVec creates a counter partitioned by the named labels.
+	NewCounterVec(name string, labelNames ...string) (*CounterVec, error)
+
+	// NewGaugeVec creates a gauge partitioned by the named labels.
+	NewGaugeVec(name string, labelNames ...string) (*GaugeVec, error)
+
+	// NewHistogramVec creates a histogram partitioned by the named labels.
+	NewHistogramVec(name string, labelNames ...string) (*HistogramVec, error)
+
+	// Close releases resources held by the client.
+	Close() error
 }
 
 // Config controls how a Client instance interacts with the host runtime.
@@ -48,33 +66,47 @@ type Config struct {
 
 	// HostCall overrides the waPC host function used for metrics operations.
 	HostCall HostCall
+
+	// MetricPrefix is prepended to every metric name, joined with an underscore,
+	// to avoid collisions across functions. When empty, names are unchanged.
+	MetricPrefix string
+
+	// Capability overrides the host capability name used for host calls, for
+	// hosts that register the capability under a custom name. When empty,
+	// "metrics" is used.
+	Capability string
 }
 
 // HostMetrics is the metrics capability client implementation.
 type HostMetrics struct {
-	runtime  sdk.RuntimeConfig
-	hostCall HostCall
+	runtime    sdk.RuntimeConfig
+	hostCall   HostCall
+	prefix     string
+	capability string
 }
 
-// Counter is a named counter metric handle.
+// Counter is a named counter metric handle. The zero value discards updates.
 type Counter struct {
-	name      string
-	namespace string
-	hostCall  HostCall
+	name       string
+	runtime    sdk.RuntimeConfig
+	hostCall   HostCall
+	capability string
 }
 
-// Gauge is a named gauge metric handle.
+// Gauge is a named gauge metric handle. The zero value discards updates.
 type Gauge struct {
-	name      string
-	namespace string
-	hostCall  HostCall
+	name       string
+	runtime    sdk.RuntimeConfig
+	hostCall   HostCall
+	capability string
 }
 
-// Histogram is a named histogram metric handle.
+// Histogram is a named histogram metric handle. The zero value discards updates.
 type Histogram struct {
-	name      string
-	namespace string
-	hostCall  HostCall
+	name       string
+	runtime    sdk.RuntimeConfig
+	hostCall   HostCall
+	capability string
 }
 
 // Ensure HostMetrics satisfies the Client interface at compile time.
@@ -92,34 +124,67 @@ func New(config Config) (*HostMetrics, error) {
 		hostCall = wapc.HostCall
 	}
 
-	return &HostMetrics{runtime: runtime, hostCall: hostCall}, nil
+	// Reject prefixes that would make every prefixed metric name invalid.
+	if config.MetricPrefix != "" && !isMetricNameValid.MatchString(config.MetricPrefix) {
+		return nil, ErrInvalidMetricPrefix
+	}
+
+	capability := config.Capability
+	if capability == "" {
+		capability = capabilityName
+	}
+
+	return &HostMetrics{runtime: runtime, hostCall: hostCall, prefix: config.MetricPrefix, capability: capability}, nil
+}
+
+// Close releases resources associated with the client. It is a no-op.
+func (c *HostMetrics) Close() error {
+	return nil
+}
+
+// metricName validates name and applies the configured prefix.
+func (c *HostMetrics) metricName(name string) (string, error) {
+	if !isMetricNameValid.MatchString(name) {
+		return "", ErrInvalidMetricName
+	}
+
+	if c.prefix == "" {
+		return name, nil
+	}
+
+	return c.prefix + prefixSeparator + name, nil
 }
 
 // NewCounter creates a named counter metric handle.
 func (c *HostMetrics) NewCounter(name string) (*Counter, error) {
-	if !isMetricNameValid.MatchString(name) {
-		return nil, ErrInvalidMetricName
+	fullName, err := c.metricName(name)
+	if err != nil {
+		return nil, err
 	}
 
-	return &Counter{name: name, namespace: c.runtime.Namespace, hostCall: c.hostCall}, nil
+	return &Counter{name: fullName, runtime: c.runtime, hostCall: c.hostCall, capability: c.capability}, nil
 }
 
 // Inc increments the counter by one.
 func (c *Counter) Inc() {
-	payload, err := (&proto.MetricsCounter{Name: c.name}).MarshalVT()
+	if c.hostCall == nil {
+		return
+	}
+	payload, err := sdk.MarshalRequest(&proto.MetricsCounter{Name: c.name})
 	if err != nil {
 		return
 	}
-	_, _ = c.hostCall(c.namespace, capabilityName, fnCounter, payload)
+	_, _ = c.runtime.Call(c.hostCall, 0, c.capability, fnCounter, payload)
 }
 
 // NewGauge creates a named gauge metric handle.
 func (c *HostMetrics) NewGauge(name string) (*Gauge, error) {
-	if !isMetricNameValid.MatchString(name) {
-		return nil, ErrInvalidMetricName
+	fullName, err := c.metricName(name)
+	if err != nil {
+		return nil, err
 	}
 
-	return &Gauge{name: name, namespace: c.runtime.Namespace, hostCall: c.hostCall}, nil
+	return &Gauge{name: fullName, runtime: c.runtime, hostCall: c.hostCall, capability: c.capability}, nil
 }
 
 // Inc increments the gauge by one.
@@ -134,27 +199,34 @@ func (g *Gauge) Dec() {
 
 // emit sends a gauge action update to the host runtime as a best-effort call.
 func (g *Gauge) emit(action string) {
-	payload, err := (&proto.MetricsGauge{Name: g.name, Action: action}).MarshalVT()
+	if g.hostCall == nil {
+		return
+	}
+	payload, err := sdk.MarshalRequest(&proto.MetricsGauge{Name: g.name, Action: action})
 	if err != nil {
 		return
 	}
-	_, _ = g.hostCall(g.namespace, capabilityName, fnGauge, payload)
+	_, _ = g.runtime.Call(g.hostCall, 0, g.capability, fnGauge, payload)
 }
 
 // NewHistogram creates a named histogram metric handle.
 func (c *HostMetrics) NewHistogram(name string) (*Histogram, error) {
-	if !isMetricNameValid.MatchString(name) {
-		return nil, ErrInvalidMetricName
+	fullName, err := c.metricName(name)
+	if err != nil {
+		return nil, err
 	}
 
-	return &Histogram{name: name, namespace: c.runtime.Namespace, hostCall: c.hostCall}, nil
+	return &Histogram{name: fullName, runtime: c.runtime, hostCall: c.hostCall, capability: c.capability}, nil
 }
 
 // Observe records a value for the histogram.
 func (h *Histogram) Observe(value float64) {
-	payload, err := (&proto.MetricsHistogram{Name: h.name, Value: value}).MarshalVT()
+	if h.hostCall == nil {
+		return
+	}
+	payload, err := sdk.MarshalRequest(&proto.MetricsHistogram{Name: h.name, Value: value})
 	if err != nil {
 		return
 	}
-	_, _ = h.hostCall(h.namespace, capabilityName, fnHistogram, payload)
+	_, _ = h.runtime.Call(h.hostCall, 0, h.capability, fnHistogram, payload)
 }
@@ -0,0 +1,15 @@
+package metrics
+
+import "testing"
+
+func BenchmarkNop(b *testing.B) {
+	client := NewNop()
+	counter, _ := client.NewCounter("requests")
+	histogram, _ := client.NewHistogram("duration")
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		counter.Inc()
+		histogram.Observe(float64(i))
+	}
+}
@@ -363,3 +363,445 @@ func TestHistogramObserve(t *testing.T) {
 		})
 	}
 }
+
+func TestMetricPrefix(t *testing.T) {
+	t.Parallel()
+
+	t.Run("invalid prefix", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := New(Config{MetricPrefix: "my-app"})
+		if !errors.Is(err, ErrInvalidMetricPrefix) {
+			t.Fatalf("unexpected error: want %v got %v", ErrInvalidMetricPrefix, err)
+		}
+	})
+
+	t.Run("invalid name with prefix", func(t *testing.T) {
+		t.Parallel()
+
+		c, err := New(Config{
+			MetricPrefix: "orders",
+			HostCall: func(string, string, string, []byte) ([]byte, error) {
+				return nil, nil
+			},
+		})
+		if err != nil {
+			t.Fatalf("New returned error: %v", err)
+		}
+
+		if _, err = c.NewCounter(""); !errors.Is(err, ErrInvalidMetricName) {
+			t.Fatalf("unexpected error: want %v got %v", ErrInvalidMetricName, err)
+		}
+	})
+
+	tt := []struct {
+		name     string
+		prefix   string
+		function string
+		invoke   func(*testing.T, *HostMetrics)
+		decode   func([]byte) (string, error)
+		wantName string
+	}{
+		{
+			name:     "counter prefixed",
+			prefix:   "orders",
+			function: fnCounter,
+			invoke: func(t *testing.T, c *HostMetrics) {
+				counter, err := c.NewCounter("requests_total")
+				if err != nil {
+					t.Fatalf("NewCounter returned error: %v", err)
+				}
+				counter.Inc()
+			},
+			decode: func(payload []byte) (string, error) {
+				var req proto.MetricsCounter
+				err := req.UnmarshalVT(payload)
+				return req.GetName(), err
+			},
+			wantName: "orders_requests_total",
+		},
+		{
+			name:     "gauge prefixed",
+			prefix:   "orders",
+			function: fnGauge,
+			invoke: func(t *testing.T, c *HostMetrics) {
+				gauge, err := c.NewGauge("queue_depth")
+				if err != nil {
+					t.Fatalf("NewGauge returned error: %v", err)
+				}
+				gauge.Inc()
+			},
+			decode: func(payload []byte) (string, error) {
+				var req proto.MetricsGauge
+				err := req.UnmarshalVT(payload)
+				return req.GetName(), err
+			},
+			wantName: "orders_queue_depth",
+		},
+		{
+			name:     "histogram prefixed",
+			prefix:   "orders",
+			function: fnHistogram,
+			invoke: func(t *testing.T, c *HostMetrics) {
+				histogram, err := c.NewHistogram("request_duration")
+				if err != nil {
+					t.Fatalf("NewHistogram returned error: %v", err)
+				}
+				histogram.Observe(1)
+			},
+			decode: func(payload []byte) (string, error) {
+				var req proto.MetricsHistogram
+				err := req.UnmarshalVT(payload)
+				return req.GetName(), err
+			},
+			wantName: "orders_request_duration",
+		},
+		{
+			name:     "no prefix unchanged",
+			function: fnCounter,
+			invoke: func(t *testing.T, c *HostMetrics) {
+				counter, err := c.NewCounter("requests_total")
+				if err != nil {
+					t.Fatalf("NewCounter returned error: %v", err)
+				}
+				counter.Inc()
+			},
+			decode: func(payload []byte) (string, error) {
+				var req proto.MetricsCounter
+				err := req.UnmarshalVT(payload)
+				return req.GetName(), err
+			},
+			wantName: "requests_total",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var captured string
+			mock, err := hostmock.New(hostmock.Config{
+				ExpectedNamespace:  "tarmac",
+				ExpectedCapability: capabilityName,
+				ExpectedFunction:   tc.function,
+				PayloadValidator: func(payload []byte) error {
+					name, decodeErr := tc.decode(payload)
+					captured = name
+					return decodeErr
+				},
+			})
+			if err != nil {
+				t.Fatalf("failed to create hostmock: %v", err)
+			}
+
+			c, err := New(Config{
+				SDKConfig:    sdk.RuntimeConfig{Namespace: "tarmac"},
+				HostCall:     mock.HostCall,
+				MetricPrefix: tc.prefix,
+			})
+			if err != nil {
+				t.Fatalf("New returned error: %v", err)
+			}
+
+			tc.invoke(t, c)
+			if captured != tc.wantName {
+				t.Fatalf("metric name mismatch: want %q got %q", tc.wantName, captured)
+			}
+		})
+	}
+}
+
+func TestCapabilityOverride(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		name       string
+		capability string
+		want       string
+	}{
+		{name: "default", want: capabilityName},
+		{name: "custom", capability: "custom-metrics", want: "custom-metrics"},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			mock, err := hostmock.New(hostmock.Config{ExpectedCapability: tc.want})
+			if err != nil {
+				t.Fatalf("failed to create hostmock: %v", err)
+			}
+
+			client, err := New(Config{HostCall: mock.HostCall, Capability: tc.capability})
+			if err != nil {
+				t.Fatalf("New returned error: %v", err)
+			}
+			counter, err := client.NewCounter("requests")
+			if err != nil {
+				t.Fatalf("NewCounter returned error: %v", err)
+			}
+			counter.Inc()
+
+			calls := mock.Calls()
+			if len(calls) != 1 || calls[0].Capability != tc.want {
+				t.Fatalf("expected one call to capability %q, got %+v", tc.want, calls)
+			}
+		})
+	}
+}
+
+func TestVec(t *testing.T) {
+	t.Parallel()
+
+	newClient := func(t *testing.T) (*HostMetrics, *hostmock.Mock) {
+		mock, err := hostmock.New(hostmock.Config{})
+		if err != nil {
+			t.Fatalf("failed to create hostmock: %v", err)
+		}
+
+		c, err := New(Config{HostCall: mock.HostCall, MetricPrefix: "api"})
+		if err != nil {
+			t.Fatalf("New returned error: %v", err)
+		}
+		return c, mock
+	}
+
+	t.Run("invalid label name", func(t *testing.T) {
+		t.Parallel()
+
+		c, _ := newClient(t)
+		if _, err := c.NewCounterVec("requests", "http-method"); !errors.Is(err, ErrInvalidLabelName) {
+			t.Fatalf("unexpected error: want %v got %v", ErrInvalidLabelName, err)
+		}
+		if _, err := c.NewGaugeVec("in flight", "method"); !errors.Is(err, ErrInvalidMetricName) {
+			t.Fatalf("unexpected error: want %v got %v", ErrInvalidMetricName, err)
+		}
+	})
+
+	t.Run("label validation", func(t *testing.T) {
+		t.Parallel()
+
+		c, mock := newClient(t)
+		vec, err := c.NewCounterVec("requests", "method", "code")
+		if err != nil {
+			t.Fatalf("NewCounterVec returned error: %v", err)
+		}
+
+		tt := []struct {
+			name   string
+			values []string
+			want   error
+		}{
+			{name: "too few", values: []string{"GET"}, want: ErrLabelCount},
+			{name: "too many", values: []string{"GET", "200", "extra"}, want: ErrLabelCount},
+			{name: "empty value", values: []string{"GET", ""}, want: ErrInvalidLabelValue},
+			{name: "invalid value", values: []string{"GET", "2 00"}, want: ErrInvalidLabelValue},
+			{name: "underscore value", values: []string{"GET", "2_00"}, want: ErrInvalidLabelValue},
+		}
+
+		for _, tc := range tt {
+			if _, err := vec.WithLabelValues(tc.values...); !errors.Is(err, tc.want) {
+				t.Fatalf("%s: unexpected error: want %v got %v", tc.name, tc.want, err)
+			}
+		}
+		if mock.Count() != 0 {
+			t.Fatalf("expected no host calls, got %d", mock.Count())
+		}
+	})
+
+	t.Run("children are cached", func(t *testing.T) {
+		t.Parallel()
+
+		c, _ := newClient(t)
+		vec, err := c.NewGaugeVec("in_flight", "method")
+		if err != nil {
+			t.Fatalf("NewGaugeVec returned error: %v", err)
+		}
+
+		first, err := vec.WithLabelValues("GET")
+		if err != nil {
+			t.Fatalf("WithLabelValues returned error: %v", err)
+		}
+		second, err := vec.WithLabelValues("GET")
+		if err != nil {
+			t.Fatalf("WithLabelValues returned error: %v", err)
+		}
+		other, err := vec.WithLabelValues("POST")
+		if err != nil {
+			t.Fatalf("WithLabelValues returned error: %v", err)
+		}
+
+		if first != second {
+			t.Fatalf("expected the same child for equal label values")
+		}
+		if first == other {
+			t.Fatalf("expected distinct children for different label values")
+		}
+	})
+
+	t.Run("distinct payloads", func(t *testing.T) {
+		t.Parallel()
+
+		c, mock := newClient(t)
+		counters, err := c.NewCounterVec("requests", "method", "code")
+		if err != nil {
+			t.Fatalf("NewCounterVec returned error: %v", err)
+		}
+		histograms, err := c.NewHistogramVec("duration", "method")
+		if err != nil {
+			t.Fatalf("NewHistogramVec returned error: %v", err)
+		}
+
+		for _, values := range [][]string{{"GET", "200"}, {"GET", "500"}, {"POST", "200"}} {
+			counter, vecErr := counters.WithLabelValues(values...)
+			if vecErr != nil {
+				t.Fatalf("WithLabelValues returned error: %v", vecErr)
+			}
+			counter.Inc()
+		}
+		histogram, err := histograms.WithLabelValues("GET")
+		if err != nil {
+			t.Fatalf("WithLabelValues returned error: %v", err)
+		}
+		histogram.Observe(0.5)
+
+		calls := mock.Calls()
+		if len(calls) != 4 {
+			t.Fatalf("expected 4 host calls, got %d", len(calls))
+		}
+
+		var got []string
+		for _, call := range calls[:3] {
+			var req proto.MetricsCounter
+			if err := req.UnmarshalVT(call.Payload); err != nil {
+				t.Fatalf("failed to decode counter payload: %v", err)
+			}
+			got = append(got, req.GetName())
+		}
+		var req proto.MetricsHistogram
+		if err := req.UnmarshalVT(calls[3].Payload); err != nil {
+			t.Fatalf("failed to decode histogram payload: %v", err)
+		}
+		got = append(got, req.GetName())
+
+		want := []string{"api_requests_GET_200", "api_requests_GET_500", "api_requests_POST_200", "api_duration_GET"}
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("metric names mismatch: want %q got %q", want, got)
+		}
+	})
+
+	t.Run("underscore placement cannot collide", func(t *testing.T) {
+		t.Parallel()
+
+		c, mock := newClient(t)
+		vec, err := c.NewCounterVec("name", "first", "second")
+		if err != nil {
+			t.Fatalf("NewCounterVec returned error: %v", err)
+		}
+
+		// Both tuples would join to api_name_a_b_c if underscores were allowed.
+		for _, values := range [][]string{{"a_b", "c"}, {"a", "b_c"}} {
+			if _, err := vec.WithLabelValues(values...); !errors.Is(err, ErrInvalidLabelValue) {
+				t.Fatalf("WithLabelValues(%q): want %v got %v", values, ErrInvalidLabelValue, err)
+			}
+		}
+
+		var got []string
+		for _, values := range [][]string{{"ab", "c"}, {"a", "bc"}} {
+			counter, vecErr := vec.WithLabelValues(values...)
+			if vecErr != nil {
+				t.Fatalf("WithLabelValues returned error: %v", vecErr)
+			}
+			counter.Inc()
+		}
+		for _, call := range mock.Calls() {
+			var req proto.MetricsCounter
+			if err := req.UnmarshalVT(call.Payload); err != nil {
+				t.Fatalf("failed to decode counter payload: %v", err)
+			}
+			got = append(got, req.GetName())
+		}
+
+		want := []string{"api_name_ab_c", "api_name_a_bc"}
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("metric names mismatch: want %q got %q", want, got)
+		}
+	})
+}
+
+func TestNop(t *testing.T) {
+	// Not parallel: testing.AllocsPerRun panics in parallel tests.
+	var client Client = NewNop()
+
+	if _, err := client.NewCounter("not a valid name"); !errors.Is(err, ErrInvalidMetricName) {
+		t.Fatalf("unexpected error: want %v got %v", ErrInvalidMetricName, err)
+	}
+	if _, err := client.NewCounterVec("not a valid name", "method"); !errors.Is(err, ErrInvalidMetricName) {
+		t.Fatalf("unexpected error: want %v got %v", ErrInvalidMetricName, err)
+	}
+	counter, err := client.NewCounter("requests")
+	if err != nil {
+		t.Fatalf("NewCounter returned error: %v", err)
+	}
+	gauge, err := client.NewGauge("queue_depth")
+	if err != nil {
+		t.Fatalf("NewGauge returned error: %v", err)
+	}
+	histogram, err := client.NewHistogram("duration")
+	if err != nil {
+		t.Fatalf("NewHistogram returned error: %v", err)
+	}
+	vec, err := client.NewCounterVec("requests", "method")
+	if err != nil {
+		t.Fatalf("NewCounterVec returned error: %v", err)
+	}
+	child, err := vec.WithLabelValues("GET")
+	if err != nil {
+		t.Fatalf("WithLabelValues returned error: %v", err)
+	}
+	if _, err := vec.WithLabelValues("GET", "extra"); !errors.Is(err, ErrLabelCount) {
+		t.Fatalf("unexpected error: want %v got %v", ErrLabelCount, err)
+	}
+	if _, err := client.NewGaugeVec("in_flight", "http-method"); !errors.Is(err, ErrInvalidLabelName) {
+		t.Fatalf("unexpected error: want %v got %v", ErrInvalidLabelName, err)
+	}
+
+	allocs := testing.AllocsPerRun(100, func() {
+		counter.Inc()
+		gauge.Inc()
+		gauge.Dec()
+		histogram.Observe(1)
+		child.Inc()
+	})
+	if allocs != 0 {
+		t.Fatalf("expected no allocations, got %v", allocs)
+	}
+}
+
+func TestClose(t *testing.T) {
+	t.Parallel()
+
+	mock, err := hostmock.New(hostmock.Config{})
+	if err != nil {
+		t.Fatalf("failed to create hostmock: %v", err)
+	}
+
+	var client Client
+	client, err = New(Config{HostCall: mock.HostCall})
+	if err != nil {
+		t.Fatalf("New returned error: %v", err)
+	}
+
+	// Close must be safe to defer unconditionally and to call more than once.
+	for i := range 2 {
+		if err := client.Close(); err != nil {
+			t.Fatalf("Close call %d returned error: %v", i+1, err)
+		}
+	}
+	if err := NewNop().Close(); err != nil {
+		t.Fatalf("Nop Close returned error: %v", err)
+	}
+	if mock.Count() != 0 {
+		t.Fatalf("expected Close to make no host calls, got %d", mock.Count())
+	}
+}
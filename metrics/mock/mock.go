@@ -0,0 +1,126 @@
+/*
+Package mock provides a metrics.Client that records every update instead of
+sending it to the host, so tests can assert on emitted metrics.
+
+Recorder wraps a real metrics client whose host call decodes each payload into
+an Observation. Handles are the same Counter, Gauge, and Histogram types the
+host-backed client returns, so code under test needs no changes beyond being
+given a Recorder in place of the real client. Labels from CounterVec and friends
+are part of the recorded Name, exactly as the host would see them.
+*/
+package mock
+
+import (
+	"sync"
+
+	proto "github.com/tarmac-project/protobuf-go/sdk/metrics"
+	"github.com/tarmac-project/sdk/metrics"
+)
+
+// Kind identifies the metric type of an Observation.
+type Kind string
+
+const (
+	// KindCounter marks a Counter.Inc update.
+	KindCounter Kind = "counter"
+
+	// KindGauge marks a Gauge.Inc or Gauge.Dec update.
+	KindGauge Kind = "gauge"
+
+	// KindHistogram marks a Histogram.Observe update.
+	KindHistogram Kind = "histogram"
+)
+
+// Observation is a single recorded metric update.
+type Observation struct {
+	// Kind is the metric type that was updated.
+	Kind Kind
+
+	// Name is the full metric name, including any prefix and label values.
+	Name string
+
+	// Action is "inc" or "dec" for gauges and empty otherwise.
+	Action string
+
+	// Value is the observed value for histograms and zero otherwise.
+	Value float64
+}
+
+// Recorder is a metrics.Client that records updates in memory. It is safe for
+// concurrent use.
+type Recorder struct {
+	*metrics.HostMetrics
+
+	mu           sync.Mutex
+	observations []Observation
+}
+
+// Ensure Recorder satisfies the metrics.Client interface at compile time.
+var _ metrics.Client = (*Recorder)(nil)
+
+// New creates a Recorder. Config is applied as it is by metrics.New, so
+// MetricPrefix and name validation behave the same; Config.HostCall is
+// replaced by the recorder.
+func New(config metrics.Config) (*Recorder, error) {
+	r := &Recorder{}
+
+	config.HostCall = r.hostCall
+	client, err := metrics.New(config)
+	if err != nil {
+		return nil, err
+	}
+	r.HostMetrics = client
+
+	return r, nil
+}
+
+// Observations returns a copy of every recorded update in order.
+func (r *Recorder) Observations() []Observation {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]Observation(nil), r.observations...)
+}
+
+// Reset discards all recorded updates.
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.observations = nil
+}
+
+// hostCall decodes a metrics payload and records it as an Observation.
+// Payloads that fail to decode are ignored, as the host would reject them.
+func (r *Recorder) hostCall(_, _, function string, payload []byte) ([]byte, error) {
+	var obs Observation
+
+	switch Kind(function) {
+	case KindCounter:
+		var req proto.MetricsCounter
+		if err := req.UnmarshalVT(payload); err != nil {
+			return nil, nil
+		}
+		obs = Observation{Kind: KindCounter, Name: req.GetName()}
+	case KindGauge:
+		var req proto.MetricsGauge
+		if err := req.UnmarshalVT(payload); err != nil {
+			return nil, nil
+		}
+		obs = Observation{Kind: KindGauge, Name: req.GetName(), Action: req.GetAction()}
+	case KindHistogram:
+		var req proto.MetricsHistogram
+		if err := req.UnmarshalVT(payload); err != nil {
+			return nil, nil
+		}
+		obs = Observation{Kind: KindHistogram, Name: req.GetName(), Value: req.GetValue()}
+	default:
+		return nil, nil
+	}
+
+	r.mu.Lock()
+	r.observations = append(r.observations, obs)
+	r.mu.Unlock()
+
+	return nil, nil
+}
@@ -0,0 +1,76 @@
+package mock
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/tarmac-project/sdk/metrics"
+)
+
+func TestRecorder(t *testing.T) {
+	t.Parallel()
+
+	t.Run("invalid prefix", func(t *testing.T) {
+		t.Parallel()
+
+		if _, err := New(metrics.Config{MetricPrefix: "my-app"}); !errors.Is(err, metrics.ErrInvalidMetricPrefix) {
+			t.Fatalf("unexpected error: want %v got %v", metrics.ErrInvalidMetricPrefix, err)
+		}
+	})
+
+	t.Run("records updates", func(t *testing.T) {
+		t.Parallel()
+
+		rec, err := New(metrics.Config{MetricPrefix: "orders"})
+		if err != nil {
+			t.Fatalf("New returned error: %v", err)
+		}
+
+		// Exercise the recorder through the interface, as code under test would.
+		var client metrics.Client = rec
+
+		counter, err := client.NewCounter("requests_total")
+		if err != nil {
+			t.Fatalf("NewCounter returned error: %v", err)
+		}
+		gauge, err := client.NewGauge("in_flight")
+		if err != nil {
+			t.Fatalf("NewGauge returned error: %v", err)
+		}
+		histogram, err := client.NewHistogram("duration")
+		if err != nil {
+			t.Fatalf("NewHistogram returned error: %v", err)
+		}
+		vec, err := client.NewCounterVec("responses", "code")
+		if err != nil {
+			t.Fatalf("NewCounterVec returned error: %v", err)
+		}
+		child, err := vec.WithLabelValues("200")
+		if err != nil {
+			t.Fatalf("WithLabelValues returned error: %v", err)
+		}
+
+		counter.Inc()
+		gauge.Inc()
+		gauge.Dec()
+		histogram.Observe(0.25)
+		child.Inc()
+
+		want := []Observation{
+			{Kind: KindCounter, Name: "orders_requests_total"},
+			{Kind: KindGauge, Name: "orders_in_flight", Action: "inc"},
+			{Kind: KindGauge, Name: "orders_in_flight", Action: "dec"},
+			{Kind: KindHistogram, Name: "orders_duration", Value: 0.25},
+			{Kind: KindCounter, Name: "orders_responses_200"},
+		}
+		if got := rec.Observations(); !reflect.DeepEqual(got, want) {
+			t.Fatalf("observations mismatch:\nwant %+v\ngot  %+v", want, got)
+		}
+
+		rec.Reset()
+		if got := rec.Observations(); len(got) != 0 {
+			t.Fatalf("expected no observations after Reset, got %+v", got)
+		}
+	})
+}
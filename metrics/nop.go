@@ -0,0 +1,87 @@
+package metrics
+
+// Shared handles returned by Nop. They hold no state, so one of each suffices.
+var (
+	nopCounter   = &Counter{}
+	nopGauge     = &Gauge{}
+	nopHistogram = &Histogram{}
+)
+
+// Nop is a Client that never calls the host. Its constructors validate metric
+// and label names exactly as HostMetrics does, so code tested against Nop fails
+// the same way in production, and return handles whose Inc, Dec, and Observe
+// do nothing, so metrics can be switched off without changing call sites.
+type Nop struct{}
+
+// Ensure Nop satisfies the Client interface at compile time.
+var _ Client = (*Nop)(nil)
+
+// NewNop returns a metrics client that discards every update.
+func NewNop() *Nop {
+	return &Nop{}
+}
+
+// NewCounter returns a counter handle that discards updates.
+func (*Nop) NewCounter(name string) (*Counter, error) {
+	if !isMetricNameValid.MatchString(name) {
+		return nil, ErrInvalidMetricName
+	}
+	return nopCounter, nil
+}
+
+// NewGauge returns a gauge handle that discards updates.
+func (*Nop) NewGauge(name string) (*Gauge, error) {
+	if !isMetricNameValid.MatchString(name) {
+		return nil, ErrInvalidMetricName
+	}
+	return nopGauge, nil
+}
+
+// NewHistogram returns a histogram handle that discards updates.
+func (*Nop) NewHistogram(name string) (*Histogram, error) {
+	if !isMetricNameValid.MatchString(name) {
+		return nil, ErrInvalidMetricName
+	}
+	return nopHistogram, nil
+}
+
+// NewCounterVec returns a counter vec whose children discard updates.
+func (*Nop) NewCounterVec(name string, labelNames ...string) (*CounterVec, error) {
+	if !isMetricNameValid.MatchString(name) {
+		return nil, ErrInvalidMetricName
+	}
+	v, err := newVec(name, labelNames, func(string) *Counter { return nopCounter })
+	if err != nil {
+		return nil, err
+	}
+	return &CounterVec{v: v}, nil
+}
+
+// NewGaugeVec returns a gauge vec whose children discard updates.
+func (*Nop) NewGaugeVec(name string, labelNames ...string) (*GaugeVec, error) {
+	if !isMetricNameValid.MatchString(name) {
+		return nil, ErrInvalidMetricName
+	}
+	v, err := newVec(name, labelNames, func(string) *Gauge { return nopGauge })
+	if err != nil {
+		return nil, err
+	}
+	return &GaugeVec{v: v}, nil
+}
+
+// NewHistogramVec returns a histogram vec whose children discard updates.
+func (*Nop) NewHistogramVec(name string, labelNames ...string) (*HistogramVec, error) {
+	if !isMetricNameValid.MatchString(name) {
+		return nil, ErrInvalidMetricName
+	}
+	v, err := newVec(name, labelNames, func(string) *Histogram { return nopHistogram })
+	if err != nil {
+		return nil, err
+	}
+	return &HistogramVec{v: v}, nil
+}
+
+// Close is a no-op.
+func (*Nop) Close() error {
+	return nil
+}
@@ -0,0 +1,177 @@
+package metrics
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+var (
+	// ErrInvalidLabelName indicates a label name that does not match the supported format.
+	ErrInvalidLabelName = errors.New("label name is invalid")
+
+	// ErrInvalidLabelValue indicates a label value that cannot be part of a metric name.
+	ErrInvalidLabelValue = errors.New("label value is invalid")
+
+	// ErrLabelCount indicates the number of label values differs from the declared label names.
+	ErrLabelCount = errors.New("label value count does not match label names")
+)
+
+// isLabelValueValid matches label values. Underscores are excluded because
+// they separate the values in a child's metric name; allowing them would let
+// different value tuples map onto the same name.
+var isLabelValueValid = regexp.MustCompile(`^[a-zA-Z0-9:]+$`)
+
+// vec caches metric handles keyed by their label values.
+type vec[T any] struct {
+	name     string
+	labels   []string
+	newChild func(name string) T
+
+	mu       sync.Mutex
+	children map[string]T
+}
+
+// newVec validates the label names and returns an empty vec for name, which
+// must already be prefixed and validated.
+func newVec[T any](name string, labels []string, newChild func(string) T) (*vec[T], error) {
+	for _, label := range labels {
+		if !isMetricNameValid.MatchString(label) {
+			return nil, ErrInvalidLabelName
+		}
+	}
+
+	return &vec[T]{
+		name:     name,
+		labels:   append([]string(nil), labels...),
+		newChild: newChild,
+		children: make(map[string]T),
+	}, nil
+}
+
+// with returns the cached child for values, creating it on first use.
+func (v *vec[T]) with(values []string) (T, error) {
+	var zero T
+	if len(values) != len(v.labels) {
+		return zero, ErrLabelCount
+	}
+
+	for _, value := range values {
+		if !isLabelValueValid.MatchString(value) {
+			return zero, ErrInvalidLabelValue
+		}
+	}
+
+	// Label values cannot contain NUL, so the key is unambiguous.
+	key := strings.Join(values, "\x00")
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	child, ok := v.children[key]
+	if !ok {
+		child = v.newChild(strings.Join(append([]string{v.name}, values...), prefixSeparator))
+		v.children[key] = child
+	}
+
+	return child, nil
+}
+
+// CounterVec partitions a counter by label values. The metrics capability has
+// no label support, so each child is a separate counter whose name is the
+// vec's name followed by the label values, joined with underscores.
+type CounterVec struct {
+	v *vec[*Counter]
+}
+
+// WithLabelValues returns the counter for values, given in the order of the
+// declared label names. Children are cached, so repeated calls with the same
+// values return the same handle.
+func (v *CounterVec) WithLabelValues(values ...string) (*Counter, error) {
+	if v.v == nil {
+		return nopCounter, nil
+	}
+	return v.v.with(values)
+}
+
+// GaugeVec partitions a gauge by label values. See CounterVec for how label
+// values map onto metric names.
+type GaugeVec struct {
+	v *vec[*Gauge]
+}
+
+// WithLabelValues returns the gauge for values, given in the order of the
+// declared label names.
+func (v *GaugeVec) WithLabelValues(values ...string) (*Gauge, error) {
+	if v.v == nil {
+		return nopGauge, nil
+	}
+	return v.v.with(values)
+}
+
+// HistogramVec partitions a histogram by label values. See CounterVec for how
+// label values map onto metric names.
+type HistogramVec struct {
+	v *vec[*Histogram]
+}
+
+// WithLabelValues returns the histogram for values, given in the order of the
+// declared label names.
+func (v *HistogramVec) WithLabelValues(values ...string) (*Histogram, error) {
+	if v.v == nil {
+		return nopHistogram, nil
+	}
+	return v.v.with(values)
+}
+
+// NewCounterVec creates a counter partitioned by labelNames.
+func (c *HostMetrics) NewCounterVec(name string, labelNames ...string) (*CounterVec, error) {
+	fullName, err := c.metricName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := newVec(fullName, labelNames, func(name string) *Counter {
+		return &Counter{name: name, runtime: c.runtime, hostCall: c.hostCall, capability: c.capability}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &CounterVec{v: v}, nil
+}
+
+// NewGaugeVec creates a gauge partitioned by labelNames.
+func (c *HostMetrics) NewGaugeVec(name string, labelNames ...string) (*GaugeVec, error) {
+	fullName, err := c.metricName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := newVec(fullName, labelNames, func(name string) *Gauge {
+		return &Gauge{name: name, runtime: c.runtime, hostCall: c.hostCall, capability: c.capability}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &GaugeVec{v: v}, nil
+}
+
+// NewHistogramVec creates a histogram partitioned by labelNames.
+func (c *HostMetrics) NewHistogramVec(name string, labelNames ...string) (*HistogramVec, error) {
+	fullName, err := c.metricName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := newVec(fullName, labelNames, func(name string) *Histogram {
+		return &Histogram{name: name, runtime: c.runtime, hostCall: c.hostCall, capability: c.capability}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &HistogramVec{v: v}, nil
+}
@@ -1,7 +1,12 @@
 package sdk
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"runtime/debug"
+	"strings"
+	"time"
 
 	wapc "github.com/wapc/wapc-guest-tinygo"
 )
@@ -9,11 +14,44 @@ import (
 // DefaultNamespace is used when no explicit namespace is provided.
 const DefaultNamespace = "tarmac"
 
+// defaultHandlerName is the waPC function name New registers Config.Handler under.
+const defaultHandlerName = "handler"
+
 var (
 	// ErrHandlerNil is returned when the provided function handler is nil.
 	ErrHandlerNil = errors.New("function handler cannot be nil")
+
+	// ErrInvalidHandlerName is returned when a handler name is empty or whitespace.
+	ErrInvalidHandlerName = errors.New("handler name is invalid")
+
+	// ErrDuplicateHandler is returned when a handler name is already registered.
+	ErrDuplicateHandler = errors.New("handler name is already registered")
+
+	// ErrMultipleHandlers is returned when both Handler and ContextHandler are set.
+	ErrMultipleHandlers = errors.New("only one of Handler and ContextHandler may be set")
+
+	// ErrHandlerTimeout is returned when a handler runs past Config.HandlerTimeout.
+	ErrHandlerTimeout = errors.New("handler exceeded its time budget")
 )
 
+// Logger receives diagnostic messages from capability clients. The logging
+// package's HostLogger satisfies it.
+type Logger interface {
+	Debug(message string)
+}
+
+// Handler is a waPC entry point that receives the request payload and returns
+// the response payload.
+type Handler func([]byte) ([]byte, error)
+
+// ContextHandler is a waPC entry point that also receives a context, which is
+// cancelled once Config.HandlerTimeout elapses.
+type ContextHandler func(ctx context.Context, payload []byte) ([]byte, error)
+
+// Middleware wraps a Handler to add behaviour such as logging, timing, or
+// authorization. It may return early without calling next.
+type Middleware func(next Handler) Handler
+
 // Config provides configuration options for SDK initialization.
 type Config struct {
 	// Namespace controls the function namespace to use for host callbacks.
@@ -22,12 +60,91 @@ type Config struct {
 
 	// Handler is the function to be registered as the main WebAssembly entry point.
 	Handler func([]byte) ([]byte, error)
+
+	// ContextHandler is registered in place of Handler for entry points that
+	// honour Config.HandlerTimeout. Exactly one of the two must be set.
+	ContextHandler ContextHandler
+
+	// HandlerTimeout is a soft time budget for every registered handler. WASM
+	// cannot preempt a running handler, so the budget is cooperative: the
+	// ContextHandler context is cancelled when it elapses, and a handler that
+	// returns after that fails with ErrHandlerTimeout. Zero or negative values
+	// disable the budget.
+	HandlerTimeout time.Duration
+
+	// DefaultTimeout bounds host calls made by capability clients built from
+	// this SDK's RuntimeConfig. Clients may override it with their own timeout.
+	// Zero or negative values disable the timeout.
+	DefaultTimeout time.Duration
+
+	// RecoverPanics converts panics in registered handlers into a *PanicError
+	// return instead of trapping the module. It is off by default so bugs are
+	// not hidden.
+	RecoverPanics bool
+
+	// Middleware wraps every registered handler. The first entry is outermost
+	// and runs first.
+	Middleware []Middleware
+
+	// Logger receives diagnostic messages, such as failed host calls, from
+	// capability clients built from this SDK's RuntimeConfig. Nil disables them.
+	Logger Logger
+
+	// Observer is invoked with the request and response of every host call made
+	// by capability clients built from this SDK's RuntimeConfig. Nil disables it.
+	Observer Observer
 }
 
 // RuntimeConfig carries configuration that is used during creation of SDK components.
 type RuntimeConfig struct {
 	// Namespace is the function namespace used to scope host interactions.
 	Namespace string
+
+	// DefaultTimeout bounds each host call when a client does not configure its
+	// own timeout. Zero or negative values disable the timeout.
+	DefaultTimeout time.Duration
+
+	// Logger receives diagnostic messages from capability clients. Nil
+	// disables them.
+	Logger Logger
+
+	// Observer is invoked around every host call made by capability clients.
+	// Nil disables it.
+	Observer Observer
+
+	// RequestID correlates the host calls made while serving one invocation.
+	// It is reported to Observer with every call, and the HTTP client forwards
+	// it as a header. Empty disables propagation.
+	RequestID string
+
+	// ctx bounds host calls made by clients built from this configuration. It
+	// is set with WithRequestContext.
+	ctx context.Context
+}
+
+// WithRequestContext returns a copy of cfg whose clients bound every host call
+// by ctx, so a handler can cancel or set a deadline for downstream calls made
+// while serving a request.
+func WithRequestContext(ctx context.Context, cfg RuntimeConfig) RuntimeConfig {
+	cfg.ctx = ctx
+	return cfg
+}
+
+// Context returns the request context set with WithRequestContext, or
+// context.Background when none was set.
+func (c RuntimeConfig) Context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
+
+// Debugf formats a message and sends it to Logger when one is configured.
+func (c RuntimeConfig) Debugf(format string, args ...any) {
+	if c.Logger == nil {
+		return
+	}
+	c.Logger.Debug(fmt.Sprintf(format, args...))
 }
 
 // SDK represents the initialized runtime with a registered waPC handler.
@@ -37,17 +154,38 @@ type SDK struct {
 
 	// handler is the function to be registered as the main WebAssembly entry point.
 	handler func([]byte) ([]byte, error)
+
+	// handlers tracks every waPC function name registered through this SDK.
+	handlers map[string]struct{}
+
+	// recoverPanics wraps registered handlers with panic recovery.
+	recoverPanics bool
+
+	// middleware wraps registered handlers, outermost first.
+	middleware []Middleware
+
+	// handlerTimeout is the soft time budget applied to registered handlers.
+	handlerTimeout time.Duration
 }
 
 // New initializes the SDK and registers the handler with waPC.
 func New(config Config) (*SDK, error) {
-	// Validate Handler is not empty
-	if config.Handler == nil {
+	// Validate exactly one Handler is provided
+	if config.Handler == nil && config.ContextHandler == nil {
 		return nil, ErrHandlerNil
 	}
 
+	if config.Handler != nil && config.ContextHandler != nil {
+		return nil, ErrMultipleHandlers
+	}
+
 	// Create runtime configuration with defaults
-	cfg := RuntimeConfig{Namespace: DefaultNamespace}
+	cfg := RuntimeConfig{
+		Namespace:      DefaultNamespace,
+		DefaultTimeout: config.DefaultTimeout,
+		Logger:         config.Logger,
+		Observer:       config.Observer,
+	}
 
 	// Override defaults with provided configuration
 	if config.Namespace != "" {
@@ -56,15 +194,101 @@ func New(config Config) (*SDK, error) {
 
 	// Create SDK instance
 	sdk := &SDK{
-		runtime: cfg,
-		handler: config.Handler,
+		runtime:        cfg,
+		handlers:       map[string]struct{}{defaultHandlerName: {}},
+		recoverPanics:  config.RecoverPanics,
+		middleware:     config.Middleware,
+		handlerTimeout: config.HandlerTimeout,
+	}
+
+	if config.ContextHandler != nil {
+		sdk.handler = sdk.wrapContext(config.ContextHandler)
+	} else {
+		sdk.handler = sdk.wrap(config.Handler)
 	}
 
 	// Register the provided handler with waPC
-	wapc.RegisterFunction("handler", sdk.handler)
+	wapc.RegisterFunction(defaultHandlerName, sdk.handler)
 
 	return sdk, nil
 }
 
+// Handle registers fn as an additional waPC entry point under name. The name
+// "handler" is reserved for Config.Handler, and each name may be registered
+// only once.
+func (s *SDK) Handle(name string, fn func([]byte) ([]byte, error)) error {
+	if strings.TrimSpace(name) == "" {
+		return ErrInvalidHandlerName
+	}
+
+	if fn == nil {
+		return ErrHandlerNil
+	}
+
+	if _, ok := s.handlers[name]; ok {
+		return fmt.Errorf("%w: %q", ErrDuplicateHandler, name)
+	}
+
+	s.handlers[name] = struct{}{}
+	wapc.RegisterFunction(name, s.wrap(fn))
+
+	return nil
+}
+
+// wrap applies the configured time budget, middleware, and panic recovery to fn.
+func (s *SDK) wrap(fn func([]byte) ([]byte, error)) func([]byte) ([]byte, error) {
+	return s.wrapContext(func(_ context.Context, payload []byte) ([]byte, error) {
+		return fn(payload)
+	})
+}
+
+// wrapContext applies the configured time budget, middleware, and panic
+// recovery to fn. The budget is innermost so middleware timing includes it, and
+// recovery is outermost so panics raised by middleware are recovered as well.
+func (s *SDK) wrapContext(fn ContextHandler) func([]byte) ([]byte, error) {
+	h := s.budget(fn)
+	for i := len(s.middleware) - 1; i >= 0; i-- {
+		h = s.middleware[i](h)
+	}
+
+	if !s.recoverPanics {
+		return h
+	}
+
+	return func(payload []byte) (resp []byte, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				resp, err = nil, &PanicError{Value: r, Stack: debug.Stack()}
+			}
+		}()
+		return h(payload)
+	}
+}
+
+// budget adapts fn to a Handler that runs under the configured time budget. A
+// handler that returns after the budget elapses fails with ErrHandlerTimeout,
+// joined with its own error or the context error, and its response is dropped.
+func (s *SDK) budget(fn ContextHandler) Handler {
+	return func(payload []byte) ([]byte, error) {
+		ctx := context.Background()
+		if s.handlerTimeout <= 0 {
+			return fn(ctx, payload)
+		}
+
+		ctx, cancel := context.WithTimeout(ctx, s.handlerTimeout)
+		defer cancel()
+
+		resp, err := fn(ctx, payload)
+		if ctx.Err() == nil {
+			return resp, err
+		}
+
+		if err == nil {
+			err = ctx.Err()
+		}
+		return nil, errors.Join(fmt.Errorf("%w (%s)", ErrHandlerTimeout, s.handlerTimeout), err)
+	}
+}
+
 // Config returns the current runtime configuration snapshot.
 func (s *SDK) Config() RuntimeConfig { return s.runtime }
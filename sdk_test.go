@@ -1,8 +1,14 @@
 package sdk
 
 import (
+	"bytes"
+	"context"
 	"errors"
+	"fmt"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 )
 
 type testCase struct {
@@ -93,3 +99,852 @@ func TestSDK_Behavior(t *testing.T) {
 		}
 	})
 }
+
+func TestDefaultTimeout(t *testing.T) {
+	h := func(b []byte) ([]byte, error) { return b, nil }
+
+	s, err := New(Config{Handler: h, DefaultTimeout: 2 * time.Second})
+	if err != nil {
+		t.Fatalf("New returned error: %v", err)
+	}
+	if got := s.Config().DefaultTimeout; got != 2*time.Second {
+		t.Fatalf("expected default timeout %v, got %v", 2*time.Second, got)
+	}
+
+	s, err = New(Config{Handler: h})
+	if err != nil {
+		t.Fatalf("New returned error: %v", err)
+	}
+	if got := s.Config().DefaultTimeout; got != 0 {
+		t.Fatalf("expected no default timeout, got %v", got)
+	}
+}
+
+func TestCallContext(t *testing.T) {
+	t.Parallel()
+
+	release := make(chan struct{})
+	t.Cleanup(func() { close(release) })
+
+	echo := func(namespace, capability, function string, payload []byte) ([]byte, error) {
+		if namespace != "ns" || capability != "cap" || function != "fn" {
+			return nil, errors.New("unexpected routing")
+		}
+		return payload, nil
+	}
+	blocking := func(string, string, string, []byte) ([]byte, error) {
+		<-release
+		return []byte("late"), nil
+	}
+
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tt := []struct {
+		name     string
+		ctx      func() (context.Context, context.CancelFunc)
+		hostCall func(string, string, string, []byte) ([]byte, error)
+		want     []byte
+		wantErr  error
+	}{
+		{
+			name:     "background calls through",
+			ctx:      func() (context.Context, context.CancelFunc) { return context.Background(), func() {} },
+			hostCall: echo,
+			want:     []byte("payload"),
+		},
+		{
+			name: "deadline not reached",
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), time.Minute)
+			},
+			hostCall: echo,
+			want:     []byte("payload"),
+		},
+		{
+			name: "host error passes through",
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), time.Minute)
+			},
+			hostCall: func(string, string, string, []byte) ([]byte, error) { return nil, ErrHostCall },
+			wantErr:  ErrHostCall,
+		},
+		{
+			name: "deadline exceeded",
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), 10*time.Millisecond)
+			},
+			hostCall: blocking,
+			wantErr:  context.DeadlineExceeded,
+		},
+		{
+			name:     "already canceled skips host",
+			ctx:      func() (context.Context, context.CancelFunc) { return canceled, func() {} },
+			hostCall: func(string, string, string, []byte) ([]byte, error) { panic("host called") },
+			wantErr:  context.Canceled,
+		},
+		{
+			name:    "nil host call",
+			ctx:     func() (context.Context, context.CancelFunc) { return context.Background(), func() {} },
+			wantErr: ErrNilHostCall,
+		},
+		{
+			name: "nil host call with deadline",
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), time.Minute)
+			},
+			wantErr: ErrNilHostCall,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := tc.ctx()
+			defer cancel()
+
+			got, err := CallContext(ctx, tc.hostCall, "ns", "cap", "fn", []byte("payload"))
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("expected error %v, got %v", tc.wantErr, err)
+			}
+			if !bytes.Equal(got, tc.want) {
+				t.Fatalf("expected response %q, got %q", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestHandle(t *testing.T) {
+	h := func(b []byte) ([]byte, error) { return b, nil }
+
+	s, err := New(Config{Namespace: "handlers", Handler: h})
+	if err != nil {
+		t.Fatalf("New returned error: %v", err)
+	}
+
+	tt := []struct {
+		name    string
+		handler string
+		fn      func([]byte) ([]byte, error)
+		wantErr error
+	}{
+		{name: "Register new name", handler: "process", fn: h},
+		{name: "Register second name", handler: "health", fn: h},
+		{name: "Duplicate name", handler: "process", fn: h, wantErr: ErrDuplicateHandler},
+		{name: "Reserved main handler", handler: "handler", fn: h, wantErr: ErrDuplicateHandler},
+		{name: "Empty name", handler: " ", fn: h, wantErr: ErrInvalidHandlerName},
+		{name: "Nil handler", handler: "nil", fn: nil, wantErr: ErrHandlerNil},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			err := s.Handle(tc.handler, tc.fn)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("expected error %v, got %v", tc.wantErr, err)
+			}
+		})
+	}
+
+	t.Run("Failed registration can be retried", func(t *testing.T) {
+		if err := s.Handle("nil", h); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	})
+
+	if got := s.Config().Namespace; got != "handlers" {
+		t.Fatalf("expected namespace %q, got %q", "handlers", got)
+	}
+}
+
+func TestRecoverPanics(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	tt := []struct {
+		name      string
+		handler   func([]byte) ([]byte, error)
+		want      []byte
+		wantErr   error
+		wantValue any
+	}{
+		{
+			name:      "Panic with value",
+			handler:   func([]byte) ([]byte, error) { panic("kaboom") },
+			wantErr:   ErrHandlerPanic,
+			wantValue: "kaboom",
+		},
+		{
+			name:      "Panic with error",
+			handler:   func([]byte) ([]byte, error) { panic(errBoom) },
+			wantErr:   errBoom,
+			wantValue: errBoom,
+		},
+		{
+			name:    "No panic",
+			handler: func(b []byte) ([]byte, error) { return b, nil },
+			want:    []byte("payload"),
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			s, err := New(Config{Handler: tc.handler, RecoverPanics: true})
+			if err != nil {
+				t.Fatalf("New returned error: %v", err)
+			}
+
+			got, err := s.handler([]byte("payload"))
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("expected error %v, got %v", tc.wantErr, err)
+			}
+			if !bytes.Equal(got, tc.want) {
+				t.Fatalf("expected response %q, got %q", tc.want, got)
+			}
+			if tc.wantErr == nil {
+				return
+			}
+
+			var panicErr *PanicError
+			if !errors.As(err, &panicErr) {
+				t.Fatalf("expected *PanicError, got %T", err)
+			}
+			if panicErr.Value != tc.wantValue {
+				t.Fatalf("expected panic value %v, got %v", tc.wantValue, panicErr.Value)
+			}
+			if len(panicErr.Stack) == 0 {
+				t.Fatal("expected stack to be captured")
+			}
+		})
+	}
+
+	t.Run("Disabled by default", func(t *testing.T) {
+		s, err := New(Config{Handler: func([]byte) ([]byte, error) { panic("kaboom") }})
+		if err != nil {
+			t.Fatalf("New returned error: %v", err)
+		}
+
+		defer func() {
+			if recover() == nil {
+				t.Fatal("expected panic to propagate")
+			}
+		}()
+		_, _ = s.handler(nil)
+	})
+}
+
+func TestMiddleware(t *testing.T) {
+	errDenied := errors.New("denied")
+
+	var order []string
+	trace := func(name string) Middleware {
+		return func(next Handler) Handler {
+			return func(b []byte) ([]byte, error) {
+				order = append(order, name+":before")
+				resp, err := next(b)
+				order = append(order, name+":after")
+				return resp, err
+			}
+		}
+	}
+	deny := func(Handler) Handler {
+		return func([]byte) ([]byte, error) {
+			order = append(order, "deny")
+			return nil, errDenied
+		}
+	}
+	handler := func(b []byte) ([]byte, error) {
+		order = append(order, "handler")
+		return b, nil
+	}
+
+	tt := []struct {
+		name       string
+		middleware []Middleware
+		want       []byte
+		wantErr    error
+		wantOrder  []string
+	}{
+		{
+			name:       "Declared order",
+			middleware: []Middleware{trace("first"), trace("second")},
+			want:       []byte("payload"),
+			wantOrder:  []string{"first:before", "second:before", "handler", "second:after", "first:after"},
+		},
+		{
+			name:       "Short circuit",
+			middleware: []Middleware{trace("first"), deny, trace("unreached")},
+			wantErr:    errDenied,
+			wantOrder:  []string{"first:before", "deny", "first:after"},
+		},
+		{
+			name:      "No middleware",
+			want:      []byte("payload"),
+			wantOrder: []string{"handler"},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			order = nil
+
+			s, err := New(Config{Handler: handler, Middleware: tc.middleware})
+			if err != nil {
+				t.Fatalf("New returned error: %v", err)
+			}
+
+			got, err := s.handler([]byte("payload"))
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("expected error %v, got %v", tc.wantErr, err)
+			}
+			if !bytes.Equal(got, tc.want) {
+				t.Fatalf("expected response %q, got %q", tc.want, got)
+			}
+			if !slices.Equal(order, tc.wantOrder) {
+				t.Fatalf("expected order %v, got %v", tc.wantOrder, order)
+			}
+		})
+	}
+
+	t.Run("Panics in middleware are recovered", func(t *testing.T) {
+		boom := func(Handler) Handler {
+			return func([]byte) ([]byte, error) { panic("middleware") }
+		}
+
+		s, err := New(Config{Handler: handler, Middleware: []Middleware{boom}, RecoverPanics: true})
+		if err != nil {
+			t.Fatalf("New returned error: %v", err)
+		}
+		if _, err := s.handler(nil); !errors.Is(err, ErrHandlerPanic) {
+			t.Fatalf("expected %v, got %v", ErrHandlerPanic, err)
+		}
+	})
+}
+
+func TestHandlerTimeout(t *testing.T) {
+	const budget = 20 * time.Millisecond
+	errCancelled := errors.New("cancelled")
+
+	respects := func(ctx context.Context, b []byte) ([]byte, error) {
+		select {
+		case <-ctx.Done():
+			return nil, errors.Join(errCancelled, ctx.Err())
+		case <-time.After(time.Second):
+			return b, nil
+		}
+	}
+	ignores := func(_ context.Context, b []byte) ([]byte, error) {
+		time.Sleep(2 * budget)
+		return b, nil
+	}
+	fast := func(_ context.Context, b []byte) ([]byte, error) { return b, nil }
+
+	tt := []struct {
+		name    string
+		config  Config
+		want    []byte
+		wantErr []error
+	}{
+		{
+			name:    "Handler respects context",
+			config:  Config{ContextHandler: respects, HandlerTimeout: budget},
+			wantErr: []error{ErrHandlerTimeout, errCancelled, context.DeadlineExceeded},
+		},
+		{
+			name:    "Handler ignores context",
+			config:  Config{ContextHandler: ignores, HandlerTimeout: budget},
+			wantErr: []error{ErrHandlerTimeout, context.DeadlineExceeded},
+		},
+		{
+			name: "Plain handler over budget",
+			config: Config{
+				Handler:        func(b []byte) ([]byte, error) { return ignores(context.Background(), b) },
+				HandlerTimeout: budget,
+			},
+			wantErr: []error{ErrHandlerTimeout},
+		},
+		{
+			name:   "Within budget",
+			config: Config{ContextHandler: fast, HandlerTimeout: budget},
+			want:   []byte("payload"),
+		},
+		{
+			name:   "Budget disabled",
+			config: Config{ContextHandler: ignores},
+			want:   []byte("payload"),
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			s, err := New(tc.config)
+			if err != nil {
+				t.Fatalf("New returned error: %v", err)
+			}
+
+			got, err := s.handler([]byte("payload"))
+			for _, want := range tc.wantErr {
+				if !errors.Is(err, want) {
+					t.Fatalf("expected error %v, got %v", want, err)
+				}
+			}
+			if len(tc.wantErr) == 0 && err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if !bytes.Equal(got, tc.want) {
+				t.Fatalf("expected response %q, got %q", tc.want, got)
+			}
+		})
+	}
+
+	t.Run("Context carries the deadline", func(t *testing.T) {
+		var hasDeadline bool
+		s, err := New(Config{
+			ContextHandler: func(ctx context.Context, b []byte) ([]byte, error) {
+				_, hasDeadline = ctx.Deadline()
+				return b, nil
+			},
+			HandlerTimeout: budget,
+		})
+		if err != nil {
+			t.Fatalf("New returned error: %v", err)
+		}
+
+		if _, err := s.handler(nil); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if !hasDeadline {
+			t.Fatal("expected handler context to carry the budget deadline")
+		}
+	})
+
+	t.Run("Budget applies to named handlers", func(t *testing.T) {
+		s, err := New(Config{ContextHandler: fast, HandlerTimeout: budget})
+		if err != nil {
+			t.Fatalf("New returned error: %v", err)
+		}
+
+		// Handle registers through wrap, so exercise it directly.
+		slow := s.wrap(func(b []byte) ([]byte, error) { return ignores(context.Background(), b) })
+		if _, err := slow(nil); !errors.Is(err, ErrHandlerTimeout) {
+			t.Fatalf("expected %v, got %v", ErrHandlerTimeout, err)
+		}
+	})
+
+	t.Run("Handler validation", func(t *testing.T) {
+		h := func(b []byte) ([]byte, error) { return b, nil }
+
+		if _, err := New(Config{Handler: h, ContextHandler: fast}); !errors.Is(err, ErrMultipleHandlers) {
+			t.Fatalf("expected %v, got %v", ErrMultipleHandlers, err)
+		}
+		if _, err := New(Config{HandlerTimeout: budget}); !errors.Is(err, ErrHandlerNil) {
+			t.Fatalf("expected %v, got %v", ErrHandlerNil, err)
+		}
+	})
+}
+
+type recordingLogger struct {
+	messages []string
+}
+
+func (l *recordingLogger) Debug(message string) { l.messages = append(l.messages, message) }
+
+func TestRuntimeLogger(t *testing.T) {
+	logger := &recordingLogger{}
+
+	s, err := New(Config{Handler: func(b []byte) ([]byte, error) { return b, nil }, Logger: logger})
+	if err != nil {
+		t.Fatalf("New returned error: %v", err)
+	}
+
+	s.Config().Debugf("%s/%s failed", "kvstore", "get")
+	if !slices.Equal(logger.messages, []string{"kvstore/get failed"}) {
+		t.Fatalf("unexpected logged messages: %q", logger.messages)
+	}
+
+	// A RuntimeConfig without a Logger discards messages.
+	RuntimeConfig{}.Debugf("dropped")
+}
+
+func TestStatusToError(t *testing.T) {
+	tt := []struct {
+		name        string
+		code        int32
+		message     string
+		wantErr     error
+		wantPartial bool
+		wantDetail  string
+	}{
+		{name: "OK", code: StatusOK},
+		{name: "Partial", code: StatusPartial, wantPartial: true},
+		{
+			name:       "Bad input",
+			code:       StatusBadInput,
+			message:    "bad query",
+			wantErr:    ErrHostError,
+			wantDetail: "(status 400): bad query",
+		},
+		{name: "Not found", code: StatusNotFound, wantErr: ErrHostError, wantDetail: "(status 404)"},
+		{name: "Error", code: StatusError, message: "boom", wantErr: ErrHostError, wantDetail: "(status 500): boom"},
+		{name: "Unknown", code: 302, wantErr: ErrHostResponseInvalid, wantDetail: "unexpected host status code 302"},
+		{name: "Zero", code: 0, wantErr: ErrHostResponseInvalid, wantDetail: "unexpected host status code 0"},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			err := StatusToError(tc.code, tc.message)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("expected error %v, got %v", tc.wantErr, err)
+			}
+			if tc.wantErr == nil && err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if tc.wantDetail != "" && !strings.Contains(err.Error(), tc.wantDetail) {
+				t.Fatalf("expected error to contain %q, got %q", tc.wantDetail, err.Error())
+			}
+			var statusErr *HostStatusError
+			if errors.Is(tc.wantErr, ErrHostError) {
+				if !errors.As(err, &statusErr) {
+					t.Fatalf("expected *HostStatusError, got %T", err)
+				}
+				if statusErr.Code != tc.code || statusErr.Message != tc.message {
+					t.Fatalf("expected code %d and message %q, got %d and %q",
+						tc.code, tc.message, statusErr.Code, statusErr.Message)
+				}
+			}
+			if got := IsPartial(tc.code); got != tc.wantPartial {
+				t.Fatalf("IsPartial(%d) = %v, want %v", tc.code, got, tc.wantPartial)
+			}
+		})
+	}
+}
+
+func TestWithRequestContext(t *testing.T) {
+	if got := (RuntimeConfig{}).Context(); got != context.Background() {
+		t.Fatalf("expected background context, got %v", got)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cfg := WithRequestContext(ctx, RuntimeConfig{Namespace: "request"})
+	if cfg.Namespace != "request" {
+		t.Fatalf("expected namespace %q, got %q", "request", cfg.Namespace)
+	}
+
+	release := make(chan struct{})
+	t.Cleanup(func() { close(release) })
+	blocking := func(string, string, string, []byte) ([]byte, error) {
+		<-release
+		return nil, nil
+	}
+
+	result := make(chan error, 1)
+	go func() {
+		_, err := CallContext(cfg.Context(), blocking, cfg.Namespace, "cap", "fn", nil)
+		result <- err
+	}()
+
+	cancel()
+	if err := <-result; !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected %v, got %v", context.Canceled, err)
+	}
+}
+
+func TestRuntimeConfigCall(t *testing.T) {
+	errHost := errors.New("host down")
+
+	tt := []struct {
+		name     string
+		hostCall func(string, string, string, []byte) ([]byte, error)
+		wantResp []byte
+		wantErr  error
+		wantLogs int
+	}{
+		{
+			name: "Success",
+			hostCall: func(_, _, _ string, payload []byte) ([]byte, error) {
+				return append([]byte("re:"), payload...), nil
+			},
+			wantResp: []byte("re:request"),
+		},
+		{
+			name:     "Failure",
+			hostCall: func(string, string, string, []byte) ([]byte, error) { return nil, errHost },
+			wantErr:  errHost,
+			wantLogs: 1,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			logger := &recordingLogger{}
+			var events []HostCallEvent
+			cfg := RuntimeConfig{
+				Namespace: "observed",
+				Logger:    logger,
+				Observer:  func(e HostCallEvent) { events = append(events, e) },
+			}
+
+			resp, err := cfg.Call(tc.hostCall, time.Minute, "kvstore", "get", []byte("request"))
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("expected error %v, got %v", tc.wantErr, err)
+			}
+			if !bytes.Equal(resp, tc.wantResp) {
+				t.Fatalf("expected response %q, got %q", tc.wantResp, resp)
+			}
+			if len(logger.messages) != tc.wantLogs {
+				t.Fatalf("expected %d logged messages, got %q", tc.wantLogs, logger.messages)
+			}
+
+			if len(events) != 1 {
+				t.Fatalf("expected one observed call, got %d", len(events))
+			}
+			e := events[0]
+			if e.Namespace != "observed" || e.Capability != "kvstore" || e.Function != "get" {
+				t.Fatalf("unexpected routing in event: %+v", e)
+			}
+			if !bytes.Equal(e.Request, []byte("request")) || !bytes.Equal(e.Response, tc.wantResp) {
+				t.Fatalf("unexpected payloads in event: %+v", e)
+			}
+			if !errors.Is(e.Err, tc.wantErr) {
+				t.Fatalf("expected event error %v, got %v", tc.wantErr, e.Err)
+			}
+		})
+	}
+
+	t.Run("Nil hooks", func(t *testing.T) {
+		resp, err := RuntimeConfig{}.Call(
+			func(string, string, string, []byte) ([]byte, error) { return []byte("ok"), nil },
+			0, "kvstore", "get", nil,
+		)
+		if err != nil || string(resp) != "ok" {
+			t.Fatalf("unexpected result: %q, %v", resp, err)
+		}
+	})
+}
+
+func TestPing(t *testing.T) {
+	errHost := errors.New("capability unavailable")
+
+	tt := []struct {
+		name     string
+		hostCall func(string, string, string, []byte) ([]byte, error)
+		wantErr  error
+	}{
+		{
+			name: "Healthy",
+			hostCall: func(_, capability, function string, payload []byte) ([]byte, error) {
+				if capability != "kvstore" || function != PingFunction || len(payload) != 0 {
+					return nil, fmt.Errorf("unexpected call %s/%s %q", capability, function, payload)
+				}
+				return nil, nil
+			},
+		},
+		{
+			name:     "Unhealthy",
+			hostCall: func(string, string, string, []byte) ([]byte, error) { return nil, errHost },
+			wantErr:  ErrHostCall,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			err := RuntimeConfig{Namespace: DefaultNamespace}.Ping(tc.hostCall, "kvstore")
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("expected error %v, got %v", tc.wantErr, err)
+			}
+			if tc.wantErr != nil && !errors.Is(err, errHost) {
+				t.Fatalf("expected host error to be wrapped, got %v", err)
+			}
+		})
+	}
+}
+
+func TestCapabilities(t *testing.T) {
+	errHost := errors.New("unknown capability")
+
+	respond := func(resp []byte, err error) func(string, string, string, []byte) ([]byte, error) {
+		return func(_, capability, function string, _ []byte) ([]byte, error) {
+			if capability != DiscoveryCapability || function != CapabilitiesFunction {
+				return nil, fmt.Errorf("unexpected call %s/%s", capability, function)
+			}
+			return resp, err
+		}
+	}
+
+	tt := []struct {
+		name     string
+		hostCall func(string, string, string, []byte) ([]byte, error)
+		want     []Capability
+		wantErr  error
+	}{
+		{
+			name: "Supported",
+			hostCall: respond(
+				[]byte(`[{"name":"kvstore","version":"v1"},{"name":"metrics"}]`), nil,
+			),
+			want: []Capability{{Name: "kvstore", Version: "v1"}, {Name: "metrics"}},
+		},
+		{
+			name:     "Empty List",
+			hostCall: respond([]byte(`[]`), nil),
+			want:     []Capability{},
+		},
+		{
+			name:     "Host Rejects Discovery",
+			hostCall: respond(nil, errHost),
+			wantErr:  ErrDiscoveryUnsupported,
+		},
+		{
+			name:     "Empty Response",
+			hostCall: respond(nil, nil),
+			wantErr:  ErrDiscoveryUnsupported,
+		},
+		{
+			name:     "Malformed Response",
+			hostCall: respond([]byte(`{"name":"kvstore"}`), nil),
+			wantErr:  ErrHostResponseInvalid,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := RuntimeConfig{Namespace: DefaultNamespace}.Capabilities(tc.hostCall)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("expected error %v, got %v", tc.wantErr, err)
+			}
+			if !slices.Equal(got, tc.want) {
+				t.Fatalf("capabilities mismatch: want %+v, got %+v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestRawCall(t *testing.T) {
+	errHost := errors.New("capability unavailable")
+
+	tt := []struct {
+		name     string
+		hostCall func(string, string, string, []byte) ([]byte, error)
+		want     []byte
+		wantErr  error
+	}{
+		{
+			name: "Success",
+			hostCall: func(namespace, capability, function string, payload []byte) ([]byte, error) {
+				if namespace != DefaultNamespace || capability != "queue" || function != "publish" {
+					return nil, fmt.Errorf("unexpected call %s/%s/%s", namespace, capability, function)
+				}
+				return append([]byte("ack:"), payload...), nil
+			},
+			want: []byte("ack:message"),
+		},
+		{
+			name:     "Host Failure",
+			hostCall: func(string, string, string, []byte) ([]byte, error) { return []byte("partial"), errHost },
+			wantErr:  ErrHostCall,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			rt := RuntimeConfig{Namespace: DefaultNamespace}
+			got, err := rt.RawCall(tc.hostCall, "queue", "publish", []byte("message"))
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("expected error %v, got %v", tc.wantErr, err)
+			}
+			if tc.wantErr != nil && !errors.Is(err, errHost) {
+				t.Fatalf("expected host error to be wrapped, got %v", err)
+			}
+			if !bytes.Equal(got, tc.want) {
+				t.Fatalf("response mismatch: want %q, got %q", tc.want, got)
+			}
+		})
+	}
+}
+
+// marshalFunc adapts a function to the MarshalVT method set.
+type marshalFunc func() ([]byte, error)
+
+func (f marshalFunc) MarshalVT() ([]byte, error) { return f() }
+
+func TestMarshalRequest(t *testing.T) {
+	errEncode := errors.New("encode failed")
+
+	tt := []struct {
+		name    string
+		msg     marshalFunc
+		want    []byte
+		wantErr error
+	}{
+		{
+			name: "Success",
+			msg:  func() ([]byte, error) { return []byte("payload"), nil },
+			want: []byte("payload"),
+		},
+		{
+			name:    "Failure",
+			msg:     func() ([]byte, error) { return []byte("partial"), errEncode },
+			wantErr: ErrMarshalRequest,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := MarshalRequest(tc.msg)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("expected error %v, got %v", tc.wantErr, err)
+			}
+			if tc.wantErr != nil && !errors.Is(err, errEncode) {
+				t.Fatalf("expected encode error to be wrapped, got %v", err)
+			}
+			if !bytes.Equal(got, tc.want) {
+				t.Fatalf("payload mismatch: want %q, got %q", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestHostCallError(t *testing.T) {
+	t.Parallel()
+
+	errTransport := errors.New("transport failed")
+
+	tt := []struct {
+		name         string
+		err          error
+		wantHostCall bool
+	}{
+		{name: "transport failure", err: errTransport, wantHostCall: true},
+		{name: "canceled", err: context.Canceled},
+		{name: "deadline exceeded", err: context.DeadlineExceeded},
+		{name: "wrapped deadline", err: fmt.Errorf("call: %w", context.DeadlineExceeded)},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := HostCallError(tc.err)
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("expected %v to be wrapped, got %v", tc.err, err)
+			}
+			if got := errors.Is(err, ErrHostCall); got != tc.wantHostCall {
+				t.Fatalf("errors.Is(err, ErrHostCall) = %v, want %v", got, tc.wantHostCall)
+			}
+		})
+	}
+}
+
+func TestSystemClock(t *testing.T) {
+	t.Parallel()
+
+	before := time.Now()
+	if now := SystemClock.Now(); now.Before(before) {
+		t.Fatalf("SystemClock.Now returned %v, before %v", now, before)
+	}
+
+	select {
+	case <-SystemClock.After(time.Millisecond):
+	case <-time.After(time.Second):
+		t.Fatal("SystemClock.After did not fire")
+	}
+}
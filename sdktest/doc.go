@@ -0,0 +1,18 @@
+/*
+Package sdktest provides testing helpers for SDK components and guest code.
+
+AssertRoundTrip guards the wire contracts capability clients depend on by
+marshaling a vtprotobuf message with MarshalVT, decoding the bytes into a fresh
+message with UnmarshalVT, and asserting the result equals the original with
+EqualMessageVT. Any generated protobuf-go message satisfies the constraint.
+
+	func TestWireContract(t *testing.T) {
+	  sdktest.AssertRoundTrip(t, &kvstore.KVStoreSet{Key: "k", Data: []byte("v")})
+	}
+
+OK, Partial, and Failed build the host status carried by every capability
+response, and StatusResponse encodes one on its own, as the host replies to
+Ping. The kvtest, sqltest, and httpclienttest packages take these statuses to
+build their capability-specific responses.
+*/
+package sdktest
@@ -0,0 +1,40 @@
+package sdktest
+
+import "testing"
+
+// Message is the vtprotobuf method set AssertRoundTrip relies on. T is the
+// message struct type, so a fresh value can be decoded without reflection.
+type Message[T any] interface {
+	*T
+
+	// MarshalVT encodes the message.
+	MarshalVT() ([]byte, error)
+
+	// UnmarshalVT decodes data into the message.
+	UnmarshalVT(data []byte) error
+
+	// EqualMessageVT reports whether the message equals other.
+	EqualMessageVT(other any) bool
+}
+
+// AssertRoundTrip marshals msg, unmarshals the bytes into a new message of the
+// same type, and fails the test if the decoded message differs from msg.
+func AssertRoundTrip[T any, M Message[T]](t testing.TB, msg M) {
+	t.Helper()
+
+	b, err := msg.MarshalVT()
+	if err != nil {
+		t.Fatalf("round trip: marshal %T: %v", msg, err)
+		return
+	}
+
+	var decoded T
+	if err := M(&decoded).UnmarshalVT(b); err != nil {
+		t.Fatalf("round trip: unmarshal %T: %v", msg, err)
+		return
+	}
+
+	if !msg.EqualMessageVT(M(&decoded)) {
+		t.Fatalf("round trip: %T mismatch: sent %+v, decoded %+v", msg, msg, M(&decoded))
+	}
+}
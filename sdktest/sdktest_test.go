@@ -0,0 +1,137 @@
+package sdktest
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	sdkproto "github.com/tarmac-project/protobuf-go/sdk"
+	sdk "github.com/tarmac-project/sdk"
+)
+
+// fakeMessage is a minimal vtprotobuf-shaped message for exercising the helper.
+type fakeMessage struct {
+	Name       string
+	Lossy      bool
+	MarshalErr error
+}
+
+func (m *fakeMessage) MarshalVT() ([]byte, error) {
+	if m.MarshalErr != nil {
+		return nil, m.MarshalErr
+	}
+	if m.Lossy {
+		return []byte("lossy"), nil
+	}
+	return []byte(m.Name), nil
+}
+
+func (m *fakeMessage) UnmarshalVT(data []byte) error {
+	if string(data) == "corrupt" {
+		return errors.New("corrupt payload")
+	}
+	m.Name = string(data)
+	return nil
+}
+
+func (m *fakeMessage) EqualMessageVT(other any) bool {
+	o, ok := other.(*fakeMessage)
+	return ok && o.Name == m.Name
+}
+
+// recorder captures failures so the helper's own failure paths can be asserted.
+type recorder struct {
+	testing.TB
+
+	failures []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Fatalf(format string, args ...any) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+func TestAssertRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		name     string
+		msg      *fakeMessage
+		wantFail string
+	}{
+		{
+			name: "stable message",
+			msg:  &fakeMessage{Name: "stable"},
+		},
+		{
+			name: "empty message",
+			msg:  &fakeMessage{},
+		},
+		{
+			name:     "marshal failure",
+			msg:      &fakeMessage{Name: "x", MarshalErr: errors.New("boom")},
+			wantFail: "marshal",
+		},
+		{
+			name:     "unmarshal failure",
+			msg:      &fakeMessage{Name: "corrupt"},
+			wantFail: "unmarshal",
+		},
+		{
+			name:     "lossy encoding",
+			msg:      &fakeMessage{Name: "original", Lossy: true},
+			wantFail: "mismatch",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			rec := &recorder{TB: t}
+			AssertRoundTrip(rec, tc.msg)
+
+			if tc.wantFail == "" {
+				if len(rec.failures) != 0 {
+					t.Fatalf("expected no failures, got %v", rec.failures)
+				}
+				return
+			}
+			if len(rec.failures) != 1 || !strings.Contains(rec.failures[0], tc.wantFail) {
+				t.Fatalf("expected one failure containing %q, got %v", tc.wantFail, rec.failures)
+			}
+		})
+	}
+}
+
+func TestStatus(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		name        string
+		status      *sdkproto.Status
+		wantCode    int32
+		wantMessage string
+	}{
+		{name: "OK", status: OK(), wantCode: sdk.StatusOK, wantMessage: "OK"},
+		{name: "Partial", status: Partial("degraded"), wantCode: sdk.StatusPartial, wantMessage: "degraded"},
+		{name: "Failed", status: Failed(sdk.StatusError, "boom"), wantCode: sdk.StatusError, wantMessage: "boom"},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var decoded sdkproto.Status
+			if err := decoded.UnmarshalVT(StatusResponse(tc.status)); err != nil {
+				t.Fatalf("StatusResponse did not decode: %v", err)
+			}
+			if decoded.GetCode() != tc.wantCode || decoded.GetStatus() != tc.wantMessage {
+				t.Fatalf("unexpected status: want %d %q got %d %q",
+					tc.wantCode, tc.wantMessage, decoded.GetCode(), decoded.GetStatus())
+			}
+		})
+	}
+}
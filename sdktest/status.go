@@ -0,0 +1,29 @@
+package sdktest
+
+import (
+	sdkproto "github.com/tarmac-project/protobuf-go/sdk"
+	sdk "github.com/tarmac-project/sdk"
+)
+
+// OK returns the status the host reports for a successful operation.
+func OK() *sdkproto.Status {
+	return &sdkproto.Status{Status: "OK", Code: sdk.StatusOK}
+}
+
+// Partial returns the status the host reports for a degraded result.
+func Partial(message string) *sdkproto.Status {
+	return &sdkproto.Status{Status: message, Code: sdk.StatusPartial}
+}
+
+// Failed returns a failure status with code, such as sdk.StatusNotFound or
+// sdk.StatusError, and message.
+func Failed(code int32, message string) *sdkproto.Status {
+	return &sdkproto.Status{Status: message, Code: code}
+}
+
+// StatusResponse encodes status on its own, as the host replies to Ping and
+// to sql Validate.
+func StatusResponse(status *sdkproto.Status) []byte {
+	b, _ := status.MarshalVT()
+	return b
+}
@@ -3,12 +3,26 @@ Package sql provides a client for executing SQL operations through the Tarmac
 host runtime.
 
 The client supports Exec for statements that do not return rows and Query for
-statements that return rows. Requests and responses are encoded with project
+statements that return rows. ExecExpectingRows reports ErrNoRowsAffected when a
+statement, such as a conditional UPDATE, matches nothing. Validate asks the
+host to check a statement without running it, reporting rejections as a
+*sdk.HostStatusError carrying the host's message. Ping issues a lightweight
+health check against the capability. QueryIter decodes the JSON-encoded Query
+data into rows one at a time through a range-over-func iterator, and
+QueryResult.Rows offers the same rows as a Next/Scan cursor that copies each
+column into a typed Go value. Requests and responses are encoded with project
 protobufs and sent through waPC host calls.
 
 Errors are returned as package sentinels and SDK host errors so callers can use
 errors.Is and errors.As for precise handling. Host partial-result responses are
 surfaced as ErrPartialResult with a PartialResultError that retains operation
-context and cause details.
+context and cause details. Calls exceeding Config.Timeout, or the SDK
+DefaultTimeout when unset, return an error matching context.DeadlineExceeded.
+ExecContext and QueryContext also stop waiting once the given context is done.
+Cancellation and deadline errors wrap the context error rather than
+sdk.ErrHostCall, since the host was abandoned rather than failing.
+
+The sqltest package builds canned host responses for tests that script the
+host through Config.HostCall or hostmock.
 */
 package sql
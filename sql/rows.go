@@ -0,0 +1,214 @@
+package sql
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// ErrScan wraps failures while scanning a row into destination values.
+var ErrScan = errors.New("failed to scan row")
+
+// Rows is a cursor over the rows of a QueryResult. Call Next to advance to each
+// row and Scan to copy its columns into Go values, then check Err once Next
+// returns false.
+//
+//	rows := result.Rows()
+//	for rows.Next() {
+//		var id int64
+//		var name string
+//		if err := rows.Scan(&id, &name); err != nil {
+//			return err
+//		}
+//	}
+//	if err := rows.Err(); err != nil {
+//		return err
+//	}
+//
+// Rows is not safe for concurrent use.
+type Rows struct {
+	columns []string
+	reader  *rowReader
+	row     map[string]any
+	err     error
+	closed  bool
+}
+
+// Rows returns a cursor over the decoded rows of r. The result data is decoded
+// lazily, one row per call to Next.
+func (r QueryResult) Rows() *Rows {
+	return &Rows{columns: r.Columns, reader: newRowReader(r.Data)}
+}
+
+// Columns returns the column names in the order Scan assigns them.
+func (rs *Rows) Columns() []string {
+	return rs.columns
+}
+
+// Next advances to the next row, reporting whether one is available. It
+// returns false at the end of the result or when a row cannot be decoded, in
+// which case Err reports the failure.
+func (rs *Rows) Next() bool {
+	if rs.closed || rs.err != nil {
+		return false
+	}
+
+	row, ok, err := rs.reader.next()
+	if !ok {
+		rs.Close()
+		return false
+	}
+	if err != nil {
+		rs.err = err
+		rs.row = nil
+		return false
+	}
+
+	rs.row = row
+	return true
+}
+
+// Scan copies the columns of the current row into dest, one destination per
+// column in Columns order.
+//
+// Supported destinations are *any, *string, *[]byte, *bool, *json.Number, and
+// pointers to the integer and floating-point kinds. Numbers are converted with
+// range checks, and strings are parsed when the destination is numeric or
+// boolean. A NULL column can only be scanned into *any, which is set to nil.
+// Failures wrap ErrScan.
+func (rs *Rows) Scan(dest ...any) error {
+	if rs.row == nil {
+		return fmt.Errorf("%w: Scan called without a successful Next", ErrScan)
+	}
+
+	if len(dest) != len(rs.columns) {
+		return fmt.Errorf("%w: expected %d destinations, got %d", ErrScan, len(rs.columns), len(dest))
+	}
+
+	for i, column := range rs.columns {
+		if err := assign(dest[i], rs.row[column]); err != nil {
+			return fmt.Errorf("%w: column %q: %w", ErrScan, column, err)
+		}
+	}
+
+	return nil
+}
+
+// Err returns the error, if any, that stopped iteration. It wraps ErrDecodeRow
+// when the result data could not be decoded.
+func (rs *Rows) Err() error {
+	return rs.err
+}
+
+// Close stops iteration so further calls to Next return false. Rows are
+// buffered in the QueryResult, so Close releases no host resources and always
+// returns nil.
+func (rs *Rows) Close() error {
+	rs.closed = true
+	rs.row = nil
+	return nil
+}
+
+// assign stores the decoded JSON value src in the value dest points to.
+func assign(dest, src any) error {
+	if d, ok := dest.(*any); ok {
+		if d == nil {
+			return errors.New("destination is a nil pointer")
+		}
+		*d = src
+		return nil
+	}
+
+	if src == nil {
+		return fmt.Errorf("cannot scan NULL into %T", dest)
+	}
+
+	switch d := dest.(type) {
+	case *string:
+		if s, ok := text(src); ok && d != nil {
+			*d = s
+			return nil
+		}
+	case *[]byte:
+		if s, ok := text(src); ok && d != nil {
+			*d = []byte(s)
+			return nil
+		}
+	case *json.Number:
+		if n, ok := src.(json.Number); ok && d != nil {
+			*d = n
+			return nil
+		}
+	case *bool:
+		if d == nil {
+			break
+		}
+		switch v := src.(type) {
+		case bool:
+			*d = v
+			return nil
+		case string:
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return err
+			}
+			*d = b
+			return nil
+		}
+	}
+
+	return assignNumber(dest, src)
+}
+
+// assignNumber stores src in an integer or floating-point destination.
+func assignNumber(dest, src any) error {
+	v := reflect.ValueOf(dest)
+	if v.Kind() != reflect.Pointer || v.IsNil() {
+		return fmt.Errorf("unsupported destination %T", dest)
+	}
+
+	s, ok := text(src)
+	if !ok {
+		return fmt.Errorf("cannot scan %T into %T", src, dest)
+	}
+
+	elem := v.Elem()
+	switch elem.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, elem.Type().Bits())
+		if err != nil {
+			return err
+		}
+		elem.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, elem.Type().Bits())
+		if err != nil {
+			return err
+		}
+		elem.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(s, elem.Type().Bits())
+		if err != nil {
+			return err
+		}
+		elem.SetFloat(n)
+	default:
+		return fmt.Errorf("cannot scan %T into %T", src, dest)
+	}
+
+	return nil
+}
+
+// text returns the textual form of a decoded string or number.
+func text(src any) (string, bool) {
+	switch v := src.(type) {
+	case string:
+		return v, true
+	case json.Number:
+		return v.String(), true
+	}
+
+	return "", false
+}
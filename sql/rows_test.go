@@ -0,0 +1,163 @@
+package sql
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestRows(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Scan Typed Destinations", func(t *testing.T) {
+		result := QueryResult{
+			Columns: []string{"id", "name", "score", "active", "raw", "note"},
+			Data: []byte(`[
+				{"id":1,"name":"alpha","score":1.5,"active":true,"raw":"YQ","note":null},
+				{"id":9007199254740993,"name":"beta","score":2,"active":"false","raw":"Yg","note":"n"}
+			]`),
+		}
+
+		type row struct {
+			id     int64
+			name   string
+			score  float64
+			active bool
+			raw    []byte
+			note   any
+		}
+
+		want := []row{
+			{id: 1, name: "alpha", score: 1.5, active: true, raw: []byte("YQ"), note: nil},
+			{id: 9007199254740993, name: "beta", score: 2, active: false, raw: []byte("Yg"), note: "n"},
+		}
+
+		rows := result.Rows()
+		var got []row
+		for rows.Next() {
+			var r row
+			if err := rows.Scan(&r.id, &r.name, &r.score, &r.active, &r.raw, &r.note); err != nil {
+				t.Fatalf("Scan returned error: %v", err)
+			}
+			got = append(got, r)
+		}
+		if err := rows.Err(); err != nil {
+			t.Fatalf("Err returned error: %v", err)
+		}
+
+		if len(got) != len(want) {
+			t.Fatalf("expected %d rows, got %d", len(want), len(got))
+		}
+		for i := range want {
+			g, w := got[i], want[i]
+			if g.id != w.id || g.name != w.name || g.score != w.score || g.active != w.active ||
+				string(g.raw) != string(w.raw) || g.note != w.note {
+				t.Fatalf("row %d mismatch: want %+v, got %+v", i, w, g)
+			}
+		}
+
+		if rows.Next() {
+			t.Fatalf("expected Next to return false after the last row")
+		}
+	})
+
+	t.Run("Scan Number Kinds", func(t *testing.T) {
+		rows := QueryResult{
+			Columns: []string{"a", "b", "c", "d"},
+			Data:    []byte(`[{"a":-7,"b":"42","c":3.25,"d":12345678901}]`),
+		}.Rows()
+		if !rows.Next() {
+			t.Fatalf("expected a row, Err: %v", rows.Err())
+		}
+
+		var (
+			a int8
+			b uint16
+			c float32
+			d json.Number
+		)
+		if err := rows.Scan(&a, &b, &c, &d); err != nil {
+			t.Fatalf("Scan returned error: %v", err)
+		}
+		if a != -7 || b != 42 || c != 3.25 || d != "12345678901" {
+			t.Fatalf("unexpected values: a=%d b=%d c=%v d=%s", a, b, c, d)
+		}
+	})
+
+	scanErrors := []struct {
+		name string
+		data string
+		dest []any
+	}{
+		{name: "NULL Into String", data: `[{"v":null}]`, dest: []any{new(string)}},
+		{name: "Overflow", data: `[{"v":300}]`, dest: []any{new(int8)}},
+		{name: "Negative Into Unsigned", data: `[{"v":-1}]`, dest: []any{new(uint)}},
+		{name: "Fraction Into Integer", data: `[{"v":1.5}]`, dest: []any{new(int)}},
+		{name: "Bool Into Number", data: `[{"v":true}]`, dest: []any{new(int)}},
+		{name: "Invalid Bool String", data: `[{"v":"maybe"}]`, dest: []any{new(bool)}},
+		{name: "Unsupported Destination", data: `[{"v":"x"}]`, dest: []any{new(map[string]any)}},
+		{name: "Non Pointer Destination", data: `[{"v":1}]`, dest: []any{0}},
+		{name: "Too Few Destinations", data: `[{"v":1}]`, dest: nil},
+	}
+
+	for _, tc := range scanErrors {
+		t.Run(tc.name, func(t *testing.T) {
+			rows := QueryResult{Columns: []string{"v"}, Data: []byte(tc.data)}.Rows()
+			if !rows.Next() {
+				t.Fatalf("expected a row, Err: %v", rows.Err())
+			}
+
+			if err := rows.Scan(tc.dest...); !errors.Is(err, ErrScan) {
+				t.Fatalf("expected ErrScan, got %v", err)
+			}
+		})
+	}
+
+	t.Run("Scan Without Next", func(t *testing.T) {
+		var v any
+		rows := QueryResult{Columns: []string{"v"}, Data: []byte(`[{"v":1}]`)}.Rows()
+		if err := rows.Scan(&v); !errors.Is(err, ErrScan) {
+			t.Fatalf("expected ErrScan, got %v", err)
+		}
+	})
+
+	t.Run("Decode Error Stops Iteration", func(t *testing.T) {
+		rows := QueryResult{Columns: []string{"v"}, Data: []byte(`[{"v":1},42,{"v":3}]`)}.Rows()
+
+		count := 0
+		for rows.Next() {
+			count++
+		}
+		if count != 1 {
+			t.Fatalf("expected 1 row before the error, got %d", count)
+		}
+		if err := rows.Err(); !errors.Is(err, ErrDecodeRow) {
+			t.Fatalf("expected ErrDecodeRow, got %v", err)
+		}
+	})
+
+	t.Run("Empty Data", func(t *testing.T) {
+		for _, data := range []string{"", "null", "[]"} {
+			rows := QueryResult{Data: []byte(data)}.Rows()
+			if rows.Next() {
+				t.Fatalf("expected no rows for %q", data)
+			}
+			if err := rows.Err(); err != nil {
+				t.Fatalf("expected no error for %q, got %v", data, err)
+			}
+		}
+	})
+
+	t.Run("Close Stops Iteration", func(t *testing.T) {
+		rows := QueryResult{Columns: []string{"v"}, Data: []byte(`[{"v":1},{"v":2}]`)}.Rows()
+		if !rows.Next() {
+			t.Fatalf("expected a row, Err: %v", rows.Err())
+		}
+		if err := rows.Close(); err != nil {
+			t.Fatalf("Close returned error: %v", err)
+		}
+		if rows.Next() {
+			t.Fatalf("expected Next to return false after Close")
+		}
+	})
+}
@@ -1,9 +1,14 @@
 package sql
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"iter"
 	"strings"
+	"time"
 
 	sdkproto "github.com/tarmac-project/protobuf-go/sdk"
 	proto "github.com/tarmac-project/protobuf-go/sdk/sql"
@@ -12,15 +17,11 @@ import (
 )
 
 const (
-	capabilityName = "sql"
+	capabilityName = "sql" // default host capability name
 	fnExec         = "exec"
 	fnQuery        = "query"
-
-	hostStatusOK       = int32(200)
-	hostStatusPartial  = int32(206)
-	hostStatusBadInput = int32(400)
-	hostStatusMissing  = int32(404)
-	hostStatusError    = int32(500)
+	fnValidate     = "validate"
+	fnPing         = sdk.PingFunction
 )
 
 var (
@@ -30,11 +31,18 @@ var (
 	// ErrPartialResult indicates the host returned a partial result.
 	ErrPartialResult = errors.New("operation completed with partial result")
 
-	// ErrMarshalRequest wraps failures while encoding the request payload.
-	ErrMarshalRequest = errors.New("failed to marshal request")
+	// ErrMarshalRequest wraps failures while encoding the request payload. It is
+	// sdk.ErrMarshalRequest, shared by every capability client.
+	ErrMarshalRequest = sdk.ErrMarshalRequest
 
 	// ErrUnmarshalResponse wraps failures while decoding the host response.
 	ErrUnmarshalResponse = errors.New("failed to unmarshal response")
+
+	// ErrDecodeRow wraps failures while decoding an individual result row.
+	ErrDecodeRow = errors.New("failed to decode row")
+
+	// ErrNoRowsAffected indicates a statement succeeded without affecting any rows.
+	ErrNoRowsAffected = errors.New("no rows affected")
 )
 
 // PartialResultError indicates an operation completed with degraded metadata and
@@ -81,9 +89,28 @@ type Client interface {
 	// Exec executes a SQL statement that does not return rows.
 	Exec(query string) (ExecResult, error)
 
+	// ExecContext is like Exec but stops waiting on the host once ctx is done.
+	ExecContext(ctx context.Context, query string) (ExecResult, error)
+
+	// ExecExpectingRows executes a SQL statement and returns ErrNoRowsAffected
+	// when it succeeds without affecting any rows.
+	ExecExpectingRows(query string) (ExecResult, error)
+
 	// Query executes a SQL statement that returns rows.
 	Query(query string) (QueryResult, error)
 
+	// QueryContext is like Query but stops waiting on the host once ctx is done.
+	QueryContext(ctx context.Context, query string) (QueryResult, error)
+
+	// QueryIter executes a SQL statement and returns an iterator over the decoded rows.
+	QueryIter(query string) (iter.Seq2[map[string]any, error], error)
+
+	// Validate checks a SQL statement on the host without executing it.
+	Validate(query string) error
+
+	// Ping checks that the host SQL capability is available.
+	Ping() error
+
 	// Close releases resources held by the client.
 	Close() error
 }
@@ -95,13 +122,24 @@ type Config struct {
 
 	// HostCall overrides the waPC host function used for SQL operations.
 	HostCall HostCall
+
+	// Timeout bounds each host call. When zero, SDKConfig.DefaultTimeout is
+	// used; a negative value disables the timeout even when a default is set.
+	Timeout time.Duration
+
+	// Capability overrides the host capability name used for host calls, for
+	// hosts that register the capability under a custom name. When empty,
+	// "sql" is used.
+	Capability string
 }
 
 // ExecResult mirrors the SQLExecResponse payload fields.
 type ExecResult struct {
 	// LastInsertID is the ID of the last inserted row, when available.
 	LastInsertID int64
-	// RowsAffected is the number of rows affected by the statement.
+	// RowsAffected is the number of rows affected by the statement. Exec
+	// reports zero without an error, so callers that need a match, such as a
+	// conditional UPDATE, should check it or use ExecExpectingRows.
 	RowsAffected int64
 }
 
@@ -115,8 +153,10 @@ type QueryResult struct {
 
 // DBClient is the SQL capability client implementation.
 type DBClient struct {
-	runtime  sdk.RuntimeConfig
-	hostCall HostCall
+	runtime    sdk.RuntimeConfig
+	hostCall   HostCall
+	timeout    time.Duration
+	capability string
 }
 
 // New creates a SQL client with namespace defaults and optional host-call override.
@@ -131,24 +171,47 @@ func New(config Config) (*DBClient, error) {
 		hostCall = wapc.HostCall
 	}
 
-	return &DBClient{runtime: runtime, hostCall: hostCall}, nil
+	// Per-client timeouts take precedence over the runtime default.
+	timeout := config.Timeout
+	if timeout == 0 {
+		timeout = runtime.DefaultTimeout
+	}
+
+	capability := config.Capability
+	if capability == "" {
+		capability = capabilityName
+	}
+
+	return &DBClient{runtime: runtime, hostCall: hostCall, timeout: timeout, capability: capability}, nil
+}
+
+// call issues a SQL host call bounded by ctx and the configured timeout.
+func (c *DBClient) call(ctx context.Context, function string, payload []byte) ([]byte, error) {
+	return sdk.WithRequestContext(ctx, c.runtime).Call(c.hostCall, c.timeout, c.capability, function, payload)
 }
 
 // Exec executes a SQL statement that does not return rows.
 func (c *DBClient) Exec(query string) (ExecResult, error) {
+	return c.ExecContext(c.runtime.Context(), query)
+}
+
+// ExecContext executes a SQL statement that does not return rows, returning
+// early with an error wrapping ctx.Err() once ctx is done. The statement may
+// still run on the host after the call is abandoned.
+func (c *DBClient) ExecContext(ctx context.Context, query string) (ExecResult, error) {
 	if strings.TrimSpace(query) == "" {
 		return ExecResult{}, ErrInvalidQuery
 	}
 
 	req := &proto.SQLExec{Query: []byte(query)}
-	b, err := req.MarshalVT()
+	b, err := sdk.MarshalRequest(req)
 	if err != nil {
-		return ExecResult{}, errors.Join(ErrMarshalRequest, err)
+		return ExecResult{}, err
 	}
 
-	respBytes, callErr := c.hostCall(c.runtime.Namespace, capabilityName, fnExec, b)
+	respBytes, callErr := c.call(ctx, fnExec, b)
 	if callErr != nil && len(respBytes) == 0 {
-		return ExecResult{}, errors.Join(sdk.ErrHostCall, callErr)
+		return ExecResult{}, sdk.HostCallError(callErr)
 	}
 
 	var resp proto.SQLExecResponse
@@ -170,7 +233,7 @@ func (c *DBClient) Exec(query string) (ExecResult, error) {
 		RowsAffected: resp.GetRowsAffected(),
 	}
 
-	if statusErr := validateStatus(resp.GetStatus(), callErr, fnExec); statusErr != nil {
+	if statusErr := c.validateStatus(resp.GetStatus(), callErr, fnExec); statusErr != nil {
 		var partialErr *PartialResultError
 		if errors.As(statusErr, &partialErr) {
 			return result, statusErr
@@ -181,21 +244,43 @@ func (c *DBClient) Exec(query string) (ExecResult, error) {
 	return result, nil
 }
 
+// ExecExpectingRows executes a SQL statement like Exec but returns
+// ErrNoRowsAffected, along with the result, when the statement affects no rows.
+// Errors from Exec, including partial results, are returned unchanged.
+func (c *DBClient) ExecExpectingRows(query string) (ExecResult, error) {
+	result, err := c.Exec(query)
+	if err != nil {
+		return result, err
+	}
+
+	if result.RowsAffected == 0 {
+		return result, ErrNoRowsAffected
+	}
+
+	return result, nil
+}
+
 // Query executes a SQL statement that returns rows.
 func (c *DBClient) Query(query string) (QueryResult, error) {
+	return c.QueryContext(c.runtime.Context(), query)
+}
+
+// QueryContext executes a SQL statement that returns rows, returning early with
+// an error wrapping ctx.Err() once ctx is done.
+func (c *DBClient) QueryContext(ctx context.Context, query string) (QueryResult, error) {
 	if strings.TrimSpace(query) == "" {
 		return QueryResult{}, ErrInvalidQuery
 	}
 
 	req := &proto.SQLQuery{Query: []byte(query)}
-	b, err := req.MarshalVT()
+	b, err := sdk.MarshalRequest(req)
 	if err != nil {
-		return QueryResult{}, errors.Join(ErrMarshalRequest, err)
+		return QueryResult{}, err
 	}
 
-	respBytes, callErr := c.hostCall(c.runtime.Namespace, capabilityName, fnQuery, b)
+	respBytes, callErr := c.call(ctx, fnQuery, b)
 	if callErr != nil && len(respBytes) == 0 {
-		return QueryResult{}, errors.Join(sdk.ErrHostCall, callErr)
+		return QueryResult{}, sdk.HostCallError(callErr)
 	}
 
 	var resp proto.SQLQueryResponse
@@ -217,7 +302,7 @@ func (c *DBClient) Query(query string) (QueryResult, error) {
 		Data:    resp.GetData(),
 	}
 
-	if statusErr := validateStatus(resp.GetStatus(), callErr, fnQuery); statusErr != nil {
+	if statusErr := c.validateStatus(resp.GetStatus(), callErr, fnQuery); statusErr != nil {
 		var partialErr *PartialResultError
 		if errors.As(statusErr, &partialErr) {
 			return result, statusErr
@@ -228,13 +313,77 @@ func (c *DBClient) Query(query string) (QueryResult, error) {
 	return result, nil
 }
 
+// QueryIter executes a SQL statement and returns an iterator that decodes the
+// buffered result rows one at a time.
+//
+// Host and validation errors are returned immediately with a nil iterator. A
+// partial result returns both the iterator and the PartialResultError. Each row
+// is decoded into a map keyed by column name, with numbers kept as json.Number
+// to preserve integer precision. A row that cannot be decoded is yielded as an
+// error wrapping ErrDecodeRow and iteration continues; malformed JSON that
+// prevents locating the next row ends iteration after yielding the error.
+func (c *DBClient) QueryIter(query string) (iter.Seq2[map[string]any, error], error) {
+	result, err := c.Query(query)
+	if err != nil {
+		var partialErr *PartialResultError
+		if !errors.As(err, &partialErr) {
+			return nil, err
+		}
+	}
+
+	return decodeRows(result.Data), err
+}
+
+// Ping issues a lightweight health check call to the SQL capability. It returns
+// nil when the host reports StatusOK and an error wrapping sdk.ErrHostError for
+// an error status.
+func (c *DBClient) Ping() error {
+	return c.statusCall(fnPing, nil)
+}
+
+// Validate asks the host to check query without executing it. It returns nil
+// when the host accepts the statement and a *sdk.HostStatusError carrying the
+// host's message when it rejects it, such as for a syntax error. Hosts that do
+// not implement validation fail the call with an error wrapping sdk.ErrHostCall.
+func (c *DBClient) Validate(query string) error {
+	if strings.TrimSpace(query) == "" {
+		return ErrInvalidQuery
+	}
+
+	b, err := sdk.MarshalRequest(&proto.SQLQuery{Query: []byte(query)})
+	if err != nil {
+		return err
+	}
+
+	return c.statusCall(fnValidate, b)
+}
+
+// statusCall issues a host call whose response is a bare status and maps that
+// status to an error.
+func (c *DBClient) statusCall(function string, payload []byte) error {
+	respBytes, callErr := c.call(c.runtime.Context(), function, payload)
+	if callErr != nil && len(respBytes) == 0 {
+		return sdk.HostCallError(callErr)
+	}
+
+	var status sdkproto.Status
+	if unmarshalErr := status.UnmarshalVT(respBytes); unmarshalErr != nil {
+		if callErr != nil {
+			return errors.Join(sdk.ErrHostCall, callErr, sdk.ErrHostResponseInvalid, ErrUnmarshalResponse, unmarshalErr)
+		}
+		return errors.Join(sdk.ErrHostResponseInvalid, ErrUnmarshalResponse, unmarshalErr)
+	}
+
+	return c.validateStatus(&status, callErr, function)
+}
+
 // Close releases resources held by the client.
 func (c *DBClient) Close() error {
 	_ = c
 	return nil
 }
 
-func validateStatus(status *sdkproto.Status, callErr error, operation string) error {
+func (c *DBClient) validateStatus(status *sdkproto.Status, callErr error, operation string) error {
 	if status == nil {
 		if callErr != nil {
 			return errors.Join(sdk.ErrHostCall, callErr, sdk.ErrHostResponseInvalid)
@@ -243,10 +392,10 @@ func validateStatus(status *sdkproto.Status, callErr error, operation string) er
 	}
 
 	code := status.GetCode()
-	switch code {
-	case hostStatusOK:
+	switch {
+	case code == sdk.StatusOK:
 		return nil
-	case hostStatusPartial:
+	case sdk.IsPartial(code):
 		cause := callErr
 		if cause == nil && status.GetStatus() != "" {
 			cause = errors.New(status.GetStatus())
@@ -255,7 +404,7 @@ func validateStatus(status *sdkproto.Status, callErr error, operation string) er
 			Operation: operation,
 			Cause:     cause,
 		}
-	case hostStatusBadInput, hostStatusMissing, hostStatusError:
+	case code == sdk.StatusBadInput, code == sdk.StatusNotFound, code == sdk.StatusError:
 		cause := error(nil)
 		if msg := status.GetStatus(); msg != "" {
 			cause = errors.New(msg)
@@ -264,8 +413,10 @@ func validateStatus(status *sdkproto.Status, callErr error, operation string) er
 			cause = errors.New("host returned an error status")
 		}
 		return &sdk.HostStatusError{
-			Capability:  capabilityName,
+			Capability:  c.capability,
 			Operation:   operation,
+			Code:        code,
+			Message:     status.GetStatus(),
 			Cause:       cause,
 			HostCallErr: callErr,
 		}
@@ -277,3 +428,113 @@ func validateStatus(status *sdkproto.Status, callErr error, operation string) er
 		return errors.Join(sdk.ErrHostResponseInvalid, statusErr)
 	}
 }
+
+// decodeRows returns an iterator over the rows of a JSON array of objects.
+func decodeRows(data []byte) iter.Seq2[map[string]any, error] {
+	return func(yield func(map[string]any, error) bool) {
+		rows := newRowReader(data)
+		for {
+			row, ok, err := rows.next()
+			if !ok || !yield(row, err) {
+				return
+			}
+		}
+	}
+}
+
+// rowReader reads the rows of a JSON array of objects one at a time.
+type rowReader struct {
+	data    []byte
+	dec     *json.Decoder
+	index   int
+	started bool
+	done    bool
+}
+
+// newRowReader returns a reader over data, which is not parsed until the first
+// call to next.
+func newRowReader(data []byte) *rowReader {
+	return &rowReader{data: data}
+}
+
+// next returns the next row, or an error wrapping ErrDecodeRow for a row that
+// cannot be decoded. ok is false once there are no more rows. A row that is
+// valid JSON but not an object is reported and reading continues; malformed
+// JSON that prevents locating the next row ends reading after its error.
+func (r *rowReader) next() (row map[string]any, ok bool, err error) {
+	if r.done {
+		return nil, false, nil
+	}
+
+	if !r.started {
+		r.started = true
+		if err := r.start(); err != nil || r.done {
+			r.done = true
+			return nil, err != nil, err
+		}
+	}
+
+	if !r.dec.More() {
+		r.done = true
+		return nil, false, nil
+	}
+
+	index := r.index
+	r.index++
+
+	// Split the stream first so a bad row does not prevent reading the next one.
+	var raw json.RawMessage
+	if decodeErr := r.dec.Decode(&raw); decodeErr != nil {
+		r.done = true
+		return nil, true, fmt.Errorf("%w: row %d: %w", ErrDecodeRow, index, decodeErr)
+	}
+
+	row, rowErr := decodeRow(raw)
+	if rowErr != nil {
+		rowErr = fmt.Errorf("%w: row %d: %w", ErrDecodeRow, index, rowErr)
+	}
+
+	return row, true, rowErr
+}
+
+// start consumes the opening bracket of the array, marking the reader done
+// when the data is empty or a JSON null, which carry no rows.
+func (r *rowReader) start() error {
+	if len(bytes.TrimSpace(r.data)) == 0 {
+		r.done = true
+		return nil
+	}
+
+	r.dec = json.NewDecoder(bytes.NewReader(r.data))
+	tok, err := r.dec.Token()
+	if err != nil {
+		return errors.Join(ErrDecodeRow, err)
+	}
+
+	if tok == nil {
+		r.done = true
+		return nil
+	}
+
+	if delim, ok := tok.(json.Delim); !ok || delim != '[' {
+		return fmt.Errorf("%w: expected JSON array, got %v", ErrDecodeRow, tok)
+	}
+
+	return nil
+}
+
+// decodeRow decodes a single JSON object, keeping numbers as json.Number.
+func decodeRow(raw json.RawMessage) (map[string]any, error) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+
+	var row map[string]any
+	if err := dec.Decode(&row); err != nil {
+		return nil, err
+	}
+	if row == nil {
+		return nil, errors.New("row is not a JSON object")
+	}
+
+	return row, nil
+}
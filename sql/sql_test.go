@@ -2,14 +2,21 @@ package sql
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	sdkproto "github.com/tarmac-project/protobuf-go/sdk"
 	proto "github.com/tarmac-project/protobuf-go/sdk/sql"
 	sdk "github.com/tarmac-project/sdk"
 	"github.com/tarmac-project/sdk/hostmock"
+	"github.com/tarmac-project/sdk/sdktest"
+	"github.com/tarmac-project/sdk/sql/sqltest"
 )
 
 func TestExec_Table(t *testing.T) {
@@ -41,7 +48,7 @@ func TestExec_Table(t *testing.T) {
 					return req.UnmarshalVT(payload)
 				},
 				Response: func() []byte {
-					return execResponse(&sdkproto.Status{Status: "OK", Code: 200}, 1, 1)
+					return sqltest.ExecResponse(&sdkproto.Status{Status: "OK", Code: 200}, 1, 1)
 				},
 			},
 			want: ExecResult{LastInsertID: 1, RowsAffected: 1},
@@ -65,7 +72,7 @@ func TestExec_Table(t *testing.T) {
 					return nil
 				},
 				Response: func() []byte {
-					return execResponse(&sdkproto.Status{Status: "OK", Code: 200}, want.LastInsertID, want.RowsAffected)
+					return sqltest.ExecResponse(sdktest.OK(), want.LastInsertID, want.RowsAffected)
 				},
 			},
 			want: want,
@@ -147,7 +154,7 @@ func TestExec_Table(t *testing.T) {
 				ExpectedCapability: capabilityName,
 				ExpectedFunction:   fnExec,
 				Response: func() []byte {
-					return execResponse(&sdkproto.Status{Status: "boom", Code: 500}, 0, 0)
+					return sqltest.ExecResponse(&sdkproto.Status{Status: "boom", Code: 500}, 0, 0)
 				},
 			},
 			wantErr: sdk.ErrHostError,
@@ -161,7 +168,7 @@ func TestExec_Table(t *testing.T) {
 				ExpectedCapability: capabilityName,
 				ExpectedFunction:   fnExec,
 				Response: func() []byte {
-					return execResponse(&sdkproto.Status{Status: "boom", Code: 500}, 0, 0)
+					return sqltest.ExecResponse(&sdkproto.Status{Status: "boom", Code: 500}, 0, 0)
 				},
 			},
 			wantErr:    sdk.ErrHostError,
@@ -176,7 +183,7 @@ func TestExec_Table(t *testing.T) {
 				ExpectedCapability: capabilityName,
 				ExpectedFunction:   fnExec,
 				Response: func() []byte {
-					return execResponse(&sdkproto.Status{Status: "bad", Code: 400}, 0, 0)
+					return sqltest.ExecResponse(&sdkproto.Status{Status: "bad", Code: 400}, 0, 0)
 				},
 			},
 			wantErr: sdk.ErrHostError,
@@ -190,7 +197,7 @@ func TestExec_Table(t *testing.T) {
 				ExpectedCapability: capabilityName,
 				ExpectedFunction:   fnExec,
 				Response: func() []byte {
-					return execResponse(&sdkproto.Status{Status: "bad input", Code: 400}, 0, 0)
+					return sqltest.ExecResponse(&sdkproto.Status{Status: "bad input", Code: 400}, 0, 0)
 				},
 			},
 			wantErr:    sdk.ErrHostError,
@@ -205,7 +212,7 @@ func TestExec_Table(t *testing.T) {
 				ExpectedCapability: capabilityName,
 				ExpectedFunction:   fnExec,
 				Response: func() []byte {
-					return execResponse(&sdkproto.Status{Status: "missing", Code: 404}, 0, 0)
+					return sqltest.ExecResponse(&sdkproto.Status{Status: "missing", Code: 404}, 0, 0)
 				},
 			},
 			wantErr: sdk.ErrHostError,
@@ -219,7 +226,7 @@ func TestExec_Table(t *testing.T) {
 				ExpectedCapability: capabilityName,
 				ExpectedFunction:   fnExec,
 				Response: func() []byte {
-					return execResponse(&sdkproto.Status{Status: "missing key", Code: 404}, 0, 0)
+					return sqltest.ExecResponse(&sdkproto.Status{Status: "missing key", Code: 404}, 0, 0)
 				},
 			},
 			wantErr:    sdk.ErrHostError,
@@ -234,7 +241,7 @@ func TestExec_Table(t *testing.T) {
 				ExpectedCapability: capabilityName,
 				ExpectedFunction:   fnExec,
 				Response: func() []byte {
-					return execResponse(
+					return sqltest.ExecResponse(
 						&sdkproto.Status{Status: "partial", Code: 206},
 						want.LastInsertID,
 						want.RowsAffected,
@@ -255,7 +262,7 @@ func TestExec_Table(t *testing.T) {
 				ExpectedCapability: capabilityName,
 				ExpectedFunction:   fnExec,
 				Response: func() []byte {
-					return execResponse(&sdkproto.Status{Status: "OK", Code: 200}, want.LastInsertID, want.RowsAffected)
+					return sqltest.ExecResponse(sdktest.OK(), want.LastInsertID, want.RowsAffected)
 				},
 			},
 			want: want,
@@ -269,7 +276,7 @@ func TestExec_Table(t *testing.T) {
 				ExpectedCapability: capabilityName,
 				ExpectedFunction:   fnExec,
 				Response: func() []byte {
-					return execResponse(&sdkproto.Status{Status: "wat", Code: 777}, 0, 0)
+					return sqltest.ExecResponse(&sdkproto.Status{Status: "wat", Code: 777}, 0, 0)
 				},
 			},
 			wantErr: sdk.ErrHostResponseInvalid,
@@ -285,7 +292,7 @@ func TestExec_Table(t *testing.T) {
 				Fail:               true,
 				Error:              errors.New("host call failed"),
 				Response: func() []byte {
-					return execResponse(&sdkproto.Status{Status: "OK", Code: 200}, want.LastInsertID, want.RowsAffected)
+					return sqltest.ExecResponse(sdktest.OK(), want.LastInsertID, want.RowsAffected)
 				},
 			},
 			want: want,
@@ -301,7 +308,7 @@ func TestExec_Table(t *testing.T) {
 				Fail:               true,
 				Error:              errors.New("host call failed"),
 				Response: func() []byte {
-					return execResponse(&sdkproto.Status{Status: "boom", Code: 500}, 0, 0)
+					return sqltest.ExecResponse(&sdkproto.Status{Status: "boom", Code: 500}, 0, 0)
 				},
 			},
 			wantErr: sdk.ErrHostCall,
@@ -317,7 +324,7 @@ func TestExec_Table(t *testing.T) {
 				Fail:               true,
 				Error:              errors.New("host call failed"),
 				Response: func() []byte {
-					return execResponse(
+					return sqltest.ExecResponse(
 						&sdkproto.Status{Status: "rows affected unavailable", Code: 206},
 						want.LastInsertID,
 						want.RowsAffected,
@@ -448,7 +455,7 @@ func TestQuery_Table(t *testing.T) {
 					return nil
 				},
 				Response: func() []byte {
-					return queryResponse(&sdkproto.Status{Status: "OK", Code: 200}, want.Columns, want.Data)
+					return sqltest.QueryResponse(&sdkproto.Status{Status: "OK", Code: 200}, want.Columns, want.Data)
 				},
 			},
 			want: want,
@@ -530,7 +537,7 @@ func TestQuery_Table(t *testing.T) {
 				ExpectedCapability: capabilityName,
 				ExpectedFunction:   fnQuery,
 				Response: func() []byte {
-					return queryResponse(&sdkproto.Status{Status: "boom", Code: 500}, nil, nil)
+					return sqltest.QueryResponse(&sdkproto.Status{Status: "boom", Code: 500}, nil, nil)
 				},
 			},
 			wantErr: sdk.ErrHostError,
@@ -544,7 +551,7 @@ func TestQuery_Table(t *testing.T) {
 				ExpectedCapability: capabilityName,
 				ExpectedFunction:   fnQuery,
 				Response: func() []byte {
-					return queryResponse(&sdkproto.Status{Status: "boom", Code: 500}, nil, nil)
+					return sqltest.QueryResponse(&sdkproto.Status{Status: "boom", Code: 500}, nil, nil)
 				},
 			},
 			wantErr:    sdk.ErrHostError,
@@ -559,7 +566,7 @@ func TestQuery_Table(t *testing.T) {
 				ExpectedCapability: capabilityName,
 				ExpectedFunction:   fnQuery,
 				Response: func() []byte {
-					return queryResponse(&sdkproto.Status{Status: "bad", Code: 400}, nil, nil)
+					return sqltest.QueryResponse(&sdkproto.Status{Status: "bad", Code: 400}, nil, nil)
 				},
 			},
 			wantErr: sdk.ErrHostError,
@@ -573,7 +580,7 @@ func TestQuery_Table(t *testing.T) {
 				ExpectedCapability: capabilityName,
 				ExpectedFunction:   fnQuery,
 				Response: func() []byte {
-					return queryResponse(&sdkproto.Status{Status: "bad input", Code: 400}, nil, nil)
+					return sqltest.QueryResponse(&sdkproto.Status{Status: "bad input", Code: 400}, nil, nil)
 				},
 			},
 			wantErr:    sdk.ErrHostError,
@@ -588,7 +595,7 @@ func TestQuery_Table(t *testing.T) {
 				ExpectedCapability: capabilityName,
 				ExpectedFunction:   fnQuery,
 				Response: func() []byte {
-					return queryResponse(&sdkproto.Status{Status: "missing", Code: 404}, nil, nil)
+					return sqltest.QueryResponse(&sdkproto.Status{Status: "missing", Code: 404}, nil, nil)
 				},
 			},
 			wantErr: sdk.ErrHostError,
@@ -602,7 +609,7 @@ func TestQuery_Table(t *testing.T) {
 				ExpectedCapability: capabilityName,
 				ExpectedFunction:   fnQuery,
 				Response: func() []byte {
-					return queryResponse(&sdkproto.Status{Status: "missing key", Code: 404}, nil, nil)
+					return sqltest.QueryResponse(&sdkproto.Status{Status: "missing key", Code: 404}, nil, nil)
 				},
 			},
 			wantErr:    sdk.ErrHostError,
@@ -617,7 +624,7 @@ func TestQuery_Table(t *testing.T) {
 				ExpectedCapability: capabilityName,
 				ExpectedFunction:   fnQuery,
 				Response: func() []byte {
-					return queryResponse(&sdkproto.Status{Status: "partial", Code: 206}, want.Columns, want.Data)
+					return sqltest.QueryResponse(sdktest.Partial("partial"), want.Columns, want.Data)
 				},
 			},
 			want:               want,
@@ -634,7 +641,7 @@ func TestQuery_Table(t *testing.T) {
 				ExpectedCapability: capabilityName,
 				ExpectedFunction:   fnQuery,
 				Response: func() []byte {
-					return queryResponse(&sdkproto.Status{Status: "OK", Code: 200}, want.Columns, want.Data)
+					return sqltest.QueryResponse(&sdkproto.Status{Status: "OK", Code: 200}, want.Columns, want.Data)
 				},
 			},
 			want: want,
@@ -648,7 +655,7 @@ func TestQuery_Table(t *testing.T) {
 				ExpectedCapability: capabilityName,
 				ExpectedFunction:   fnQuery,
 				Response: func() []byte {
-					return queryResponse(&sdkproto.Status{Status: "wat", Code: 777}, nil, nil)
+					return sqltest.QueryResponse(&sdkproto.Status{Status: "wat", Code: 777}, nil, nil)
 				},
 			},
 			wantErr: sdk.ErrHostResponseInvalid,
@@ -664,7 +671,7 @@ func TestQuery_Table(t *testing.T) {
 				Fail:               true,
 				Error:              errors.New("host call failed"),
 				Response: func() []byte {
-					return queryResponse(&sdkproto.Status{Status: "OK", Code: 200}, want.Columns, want.Data)
+					return sqltest.QueryResponse(&sdkproto.Status{Status: "OK", Code: 200}, want.Columns, want.Data)
 				},
 			},
 			want: want,
@@ -680,7 +687,7 @@ func TestQuery_Table(t *testing.T) {
 				Fail:               true,
 				Error:              errors.New("host call failed"),
 				Response: func() []byte {
-					return queryResponse(&sdkproto.Status{Status: "boom", Code: 500}, nil, nil)
+					return sqltest.QueryResponse(&sdkproto.Status{Status: "boom", Code: 500}, nil, nil)
 				},
 			},
 			wantErr: sdk.ErrHostCall,
@@ -696,7 +703,7 @@ func TestQuery_Table(t *testing.T) {
 				Fail:               true,
 				Error:              errors.New("host call failed"),
 				Response: func() []byte {
-					return queryResponse(
+					return sqltest.QueryResponse(
 						&sdkproto.Status{Status: "partial rows", Code: 206},
 						want.Columns,
 						want.Data,
@@ -788,24 +795,391 @@ func TestQuery_Table(t *testing.T) {
 	}
 }
 
-func execResponse(status *sdkproto.Status, lastInsertID, rowsAffected int64) []byte {
-	resp := &proto.SQLExecResponse{
-		Status:       status,
-		LastInsertId: lastInsertID,
-		RowsAffected: rowsAffected,
+func TestQueryIter(t *testing.T) {
+	t.Parallel()
+
+	query := "SELECT id, name FROM table_name"
+	ok := &sdkproto.Status{Status: "OK", Code: 200}
+
+	type step struct {
+		row     map[string]any
+		wantErr bool
+	}
+
+	tt := []struct {
+		name      string
+		hostCall  HostCall
+		wantErr   error
+		wantSteps []step
+	}{
+		{
+			name: "Rows",
+			hostCall: func(string, string, string, []byte) ([]byte, error) {
+				data := []byte(`[{"id":1,"name":"alpha"},{"id":2,"name":null}]`)
+				return sqltest.QueryResponse(ok, []string{"id", "name"}, data), nil
+			},
+			wantSteps: []step{
+				{row: map[string]any{"id": json.Number("1"), "name": "alpha"}},
+				{row: map[string]any{"id": json.Number("2"), "name": nil}},
+			},
+		},
+		{
+			name: "Large Integer Precision",
+			hostCall: func(string, string, string, []byte) ([]byte, error) {
+				return sqltest.QueryResponse(ok, []string{"id"}, []byte(`[{"id":9007199254740993}]`)), nil
+			},
+			wantSteps: []step{
+				{row: map[string]any{"id": json.Number("9007199254740993")}},
+			},
+		},
+		{
+			name: "Malformed Row Mid Iteration",
+			hostCall: func(string, string, string, []byte) ([]byte, error) {
+				return sqltest.QueryResponse(ok, []string{"id"}, []byte(`[{"id":1},42,null,{"id":3}]`)), nil
+			},
+			wantSteps: []step{
+				{row: map[string]any{"id": json.Number("1")}},
+				{wantErr: true},
+				{wantErr: true},
+				{row: map[string]any{"id": json.Number("3")}},
+			},
+		},
+		{
+			name: "Truncated Data Stops Iteration",
+			hostCall: func(string, string, string, []byte) ([]byte, error) {
+				return sqltest.QueryResponse(ok, []string{"id"}, []byte(`[{"id":1},{"id":`)), nil
+			},
+			wantSteps: []step{
+				{row: map[string]any{"id": json.Number("1")}},
+				{wantErr: true},
+			},
+		},
+		{
+			name: "Non Array Data",
+			hostCall: func(string, string, string, []byte) ([]byte, error) {
+				return sqltest.QueryResponse(ok, []string{"id"}, []byte(`{"id":1}`)), nil
+			},
+			wantSteps: []step{{wantErr: true}},
+		},
+		{
+			name: "Empty Data",
+			hostCall: func(string, string, string, []byte) ([]byte, error) {
+				return sqltest.QueryResponse(ok, nil, nil), nil
+			},
+		},
+		{
+			name: "Null Data",
+			hostCall: func(string, string, string, []byte) ([]byte, error) {
+				return sqltest.QueryResponse(ok, nil, []byte("null")), nil
+			},
+		},
+		{
+			name: "Partial Result Still Iterates",
+			hostCall: func(string, string, string, []byte) ([]byte, error) {
+				status := &sdkproto.Status{Status: "truncated", Code: 206}
+				return sqltest.QueryResponse(status, []string{"id"}, []byte(`[{"id":1}]`)), nil
+			},
+			wantErr: ErrPartialResult,
+			wantSteps: []step{
+				{row: map[string]any{"id": json.Number("1")}},
+			},
+		},
+		{
+			name: "Host Error",
+			hostCall: func(string, string, string, []byte) ([]byte, error) {
+				return nil, errors.New("host down")
+			},
+			wantErr: sdk.ErrHostCall,
+		},
+	}
+
+	for i := range tt {
+		tc := tt[i]
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := newClient(t, "tarmac", nil, tc.hostCall)
+			rows, err := client.QueryIter(query)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("expected error %v, got %v", tc.wantErr, err)
+			}
+			if rows == nil {
+				if len(tc.wantSteps) > 0 {
+					t.Fatal("expected an iterator, got nil")
+				}
+				return
+			}
+
+			var got []step
+			for row, rowErr := range rows {
+				if rowErr != nil && !errors.Is(rowErr, ErrDecodeRow) {
+					t.Fatalf("expected row error to wrap %v, got %v", ErrDecodeRow, rowErr)
+				}
+				got = append(got, step{row: row, wantErr: rowErr != nil})
+			}
+			if !reflect.DeepEqual(got, tc.wantSteps) {
+				t.Fatalf("rows mismatch: want %+v got %+v", tc.wantSteps, got)
+			}
+		})
+	}
+
+	t.Run("Early Break", func(t *testing.T) {
+		t.Parallel()
+
+		client := newClient(t, "tarmac", nil, func(string, string, string, []byte) ([]byte, error) {
+			return sqltest.QueryResponse(ok, []string{"id"}, []byte(`[{"id":1},{"id":2},{"id":3}]`)), nil
+		})
+		rows, err := client.QueryIter(query)
+		if err != nil {
+			t.Fatalf("QueryIter returned error: %v", err)
+		}
+
+		count := 0
+		for range rows {
+			count++
+			break
+		}
+		if count != 1 {
+			t.Fatalf("expected iteration to stop after 1 row, got %d", count)
+		}
+	})
+}
+
+func TestExecExpectingRows(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		name     string
+		response []byte
+		want     ExecResult
+		wantErr  error
+	}{
+		{
+			name:     "rows affected",
+			response: sqltest.ExecResponse(&sdkproto.Status{Status: "OK", Code: 200}, 7, 2),
+			want:     ExecResult{LastInsertID: 7, RowsAffected: 2},
+		},
+		{
+			name:     "no rows affected",
+			response: sqltest.ExecResponse(&sdkproto.Status{Status: "OK", Code: 200}, 0, 0),
+			want:     ExecResult{},
+			wantErr:  ErrNoRowsAffected,
+		},
+		{
+			name:     "host error takes precedence",
+			response: sqltest.ExecResponse(&sdkproto.Status{Status: "boom", Code: 500}, 0, 0),
+			wantErr:  sdk.ErrHostError,
+		},
+		{
+			name:     "partial result is passed through",
+			response: sqltest.ExecResponse(&sdkproto.Status{Status: "degraded", Code: 206}, 0, 0),
+			wantErr:  ErrPartialResult,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			mock, err := hostmock.New(hostmock.Config{
+				ExpectedCapability: capabilityName,
+				ExpectedFunction:   fnExec,
+				Response:           func() []byte { return tc.response },
+			})
+			if err != nil {
+				t.Fatalf("failed to create hostmock: %v", err)
+			}
+
+			client, err := New(Config{HostCall: mock.HostCall})
+			if err != nil {
+				t.Fatalf("New returned error: %v", err)
+			}
+
+			got, err := client.ExecExpectingRows("UPDATE t SET v = 1 WHERE id = 1")
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("expected error %v, got %v", tc.wantErr, err)
+			}
+			if errors.Is(err, ErrNoRowsAffected) && !errors.Is(tc.wantErr, ErrNoRowsAffected) {
+				t.Fatalf("unexpected ErrNoRowsAffected: %v", err)
+			}
+			if got != tc.want {
+				t.Fatalf("result mismatch: want %+v, got %+v", tc.want, got)
+			}
+		})
 	}
-	b, _ := resp.MarshalVT()
-	return b
 }
 
-func queryResponse(status *sdkproto.Status, columns []string, data []byte) []byte {
-	resp := &proto.SQLQueryResponse{
-		Status:  status,
-		Columns: columns,
-		Data:    data,
+func TestTimeout(t *testing.T) {
+	t.Parallel()
+
+	release := make(chan struct{})
+	t.Cleanup(func() { close(release) })
+
+	blocking := func(string, string, string, []byte) ([]byte, error) {
+		<-release
+		return nil, nil
+	}
+
+	tt := []struct {
+		name    string
+		runtime sdk.RuntimeConfig
+		timeout time.Duration
+	}{
+		{name: "inherits runtime default", runtime: sdk.RuntimeConfig{DefaultTimeout: 10 * time.Millisecond}},
+		{
+			name:    "client override wins",
+			runtime: sdk.RuntimeConfig{DefaultTimeout: time.Hour},
+			timeout: 10 * time.Millisecond,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			client, err := New(Config{SDKConfig: tc.runtime, HostCall: blocking, Timeout: tc.timeout})
+			if err != nil {
+				t.Fatalf("New returned error: %v", err)
+			}
+			if _, err := client.Exec("DELETE FROM t"); !errors.Is(err, context.DeadlineExceeded) {
+				t.Fatalf("Exec: expected %v, got %v", context.DeadlineExceeded, err)
+			}
+			if _, err := client.Query("SELECT 1"); !errors.Is(err, context.DeadlineExceeded) {
+				t.Fatalf("Query: expected %v, got %v", context.DeadlineExceeded, err)
+			}
+		})
 	}
-	b, _ := resp.MarshalVT()
-	return b
+}
+
+func TestContextCancellation(t *testing.T) {
+	t.Parallel()
+
+	ops := []struct {
+		name string
+		call func(Client, context.Context) error
+	}{
+		{
+			name: "exec",
+			call: func(c Client, ctx context.Context) error {
+				_, err := c.ExecContext(ctx, "DELETE FROM t")
+				return err
+			},
+		},
+		{
+			name: "query",
+			call: func(c Client, ctx context.Context) error {
+				_, err := c.QueryContext(ctx, "SELECT 1")
+				return err
+			},
+		},
+	}
+
+	for _, op := range ops {
+		t.Run(op.name, func(t *testing.T) {
+			t.Parallel()
+
+			block := make(chan struct{})
+			t.Cleanup(func() { close(block) })
+
+			mock, err := hostmock.New(hostmock.Config{Block: block})
+			if err != nil {
+				t.Fatalf("failed to create hostmock: %v", err)
+			}
+
+			client, err := New(Config{HostCall: mock.HostCall})
+			if err != nil {
+				t.Fatalf("New returned error: %v", err)
+			}
+
+			t.Run("cancelled before call", func(t *testing.T) {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+
+				err := op.call(client, ctx)
+				if !errors.Is(err, context.Canceled) || errors.Is(err, sdk.ErrHostCall) {
+					t.Fatalf("expected %v without %v, got %v", context.Canceled, sdk.ErrHostCall, err)
+				}
+				if got := mock.Count(); got != 0 {
+					t.Fatalf("expected no host calls, got %d", got)
+				}
+			})
+
+			t.Run("deadline while blocked", func(t *testing.T) {
+				ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+				defer cancel()
+
+				err := op.call(client, ctx)
+				if !errors.Is(err, context.DeadlineExceeded) || errors.Is(err, sdk.ErrHostCall) {
+					t.Fatalf("expected %v without %v, got %v", context.DeadlineExceeded, sdk.ErrHostCall, err)
+				}
+			})
+		})
+	}
+
+	t.Run("request context bounds calls", func(t *testing.T) {
+		t.Parallel()
+
+		block := make(chan struct{})
+		t.Cleanup(func() { close(block) })
+
+		mock, err := hostmock.New(hostmock.Config{Block: block})
+		if err != nil {
+			t.Fatalf("failed to create hostmock: %v", err)
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		client, err := New(Config{SDKConfig: sdk.WithRequestContext(ctx, sdk.RuntimeConfig{}), HostCall: mock.HostCall})
+		if err != nil {
+			t.Fatalf("New returned error: %v", err)
+		}
+
+		if _, err := client.Query("SELECT 1"); !errors.Is(err, context.Canceled) {
+			t.Fatalf("Query: expected %v, got %v", context.Canceled, err)
+		}
+		if _, err := client.Exec("DELETE FROM t"); !errors.Is(err, context.Canceled) {
+			t.Fatalf("Exec: expected %v, got %v", context.Canceled, err)
+		}
+		if err := client.Ping(); !errors.Is(err, context.Canceled) {
+			t.Fatalf("Ping: expected %v, got %v", context.Canceled, err)
+		}
+		if got := mock.Count(); got != 0 {
+			t.Fatalf("expected no host calls, got %d", got)
+		}
+	})
+}
+
+func TestWireRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	t.Run("SQLExec", func(t *testing.T) {
+		t.Parallel()
+		sdktest.AssertRoundTrip(t, &proto.SQLExec{Query: []byte("DELETE FROM t WHERE id = 1")})
+	})
+
+	t.Run("SQLExecResponse", func(t *testing.T) {
+		t.Parallel()
+		sdktest.AssertRoundTrip(t, &proto.SQLExecResponse{
+			Status:       &sdkproto.Status{Status: "OK", Code: 200},
+			LastInsertId: 42,
+			RowsAffected: 3,
+		})
+	})
+
+	t.Run("SQLQuery", func(t *testing.T) {
+		t.Parallel()
+		sdktest.AssertRoundTrip(t, &proto.SQLQuery{Query: []byte("SELECT id FROM t")})
+	})
+
+	t.Run("SQLQueryResponse", func(t *testing.T) {
+		t.Parallel()
+		sdktest.AssertRoundTrip(t, &proto.SQLQueryResponse{
+			Status:  &sdkproto.Status{Status: "OK", Code: 200},
+			Columns: []string{"id", "name"},
+			Data:    []byte(`[{"id":1,"name":"alpha"}]`),
+		})
+	})
 }
 
 func newClient(t *testing.T, namespace string, cfg *hostmock.Config, hostCall HostCall) Client {
@@ -819,10 +1193,7 @@ func newClient(t *testing.T, namespace string, cfg *hostmock.Config, hostCall Ho
 		}
 		hostCall = mock.HostCall
 	case hostCall == nil:
-		hostCall = func(string, string, string, []byte) ([]byte, error) {
-			t.Fatalf("unexpected host call")
-			return nil, nil
-		}
+		hostCall = hostmock.Unexpected(t)
 	}
 
 	client, err := New(Config{
@@ -851,3 +1222,272 @@ func equalQueryResult(got, want QueryResult) bool {
 	}
 	return bytes.Equal(got.Data, want.Data)
 }
+
+func TestPing(t *testing.T) {
+	t.Parallel()
+
+	status := func(code int32, message string) func() []byte {
+		return func() []byte {
+			b, _ := (&sdkproto.Status{Code: code, Status: message}).MarshalVT()
+			return b
+		}
+	}
+
+	tt := []struct {
+		name    string
+		cfg     hostmock.Config
+		wantErr error
+	}{
+		{
+			name:    "healthy",
+			cfg:     hostmock.Config{Response: status(200, "OK")},
+			wantErr: nil,
+		},
+		{
+			name:    "unhealthy status",
+			cfg:     hostmock.Config{Response: status(500, "unavailable")},
+			wantErr: sdk.ErrHostError,
+		},
+		{
+			name:    "host call failure",
+			cfg:     hostmock.Config{Fail: true, Error: errors.New("no such function")},
+			wantErr: sdk.ErrHostCall,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			tc.cfg.ExpectedCapability = capabilityName
+			tc.cfg.ExpectedFunction = sdk.PingFunction
+			tc.cfg.PayloadValidator = func(payload []byte) error {
+				if len(payload) != 0 {
+					return fmt.Errorf("expected empty payload, got %q", payload)
+				}
+				return nil
+			}
+			mock, err := hostmock.New(tc.cfg)
+			if err != nil {
+				t.Fatalf("failed to create hostmock: %v", err)
+			}
+
+			client, err := New(Config{HostCall: mock.HostCall})
+			if err != nil {
+				t.Fatalf("New returned error: %v", err)
+			}
+
+			if err := client.Ping(); !errors.Is(err, tc.wantErr) {
+				t.Fatalf("expected %v, got %v", tc.wantErr, err)
+			}
+			if got := mock.Count(); got != 1 {
+				t.Fatalf("expected one host call, got %d", got)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	status := func(code int32, message string) func() []byte {
+		return func() []byte {
+			b, _ := (&sdkproto.Status{Code: code, Status: message}).MarshalVT()
+			return b
+		}
+	}
+
+	tt := []struct {
+		name      string
+		query     string
+		cfg       hostmock.Config
+		wantErr   error
+		wantMsg   string
+		wantCalls int
+	}{
+		{
+			name:      "valid statement",
+			query:     "SELECT id FROM users",
+			cfg:       hostmock.Config{Response: status(200, "OK")},
+			wantCalls: 1,
+		},
+		{
+			name:      "invalid statement",
+			query:     "SELEC id FROM users",
+			cfg:       hostmock.Config{Response: status(400, `syntax error at or near "SELEC"`)},
+			wantErr:   sdk.ErrHostError,
+			wantMsg:   `syntax error at or near "SELEC"`,
+			wantCalls: 1,
+		},
+		{
+			name:      "host without validation",
+			query:     "SELECT 1",
+			cfg:       hostmock.Config{Fail: true, Error: errors.New("no such function")},
+			wantErr:   sdk.ErrHostCall,
+			wantCalls: 1,
+		},
+		{
+			name:    "empty query",
+			query:   "  ",
+			wantErr: ErrInvalidQuery,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			tc.cfg.ExpectedCapability = capabilityName
+			tc.cfg.ExpectedFunction = fnValidate
+			tc.cfg.PayloadValidator = func(payload []byte) error {
+				var req proto.SQLQuery
+				if err := req.UnmarshalVT(payload); err != nil {
+					return err
+				}
+				if string(req.GetQuery()) != tc.query {
+					return fmt.Errorf("expected query %q, got %q", tc.query, req.GetQuery())
+				}
+				return nil
+			}
+			mock, err := hostmock.New(tc.cfg)
+			if err != nil {
+				t.Fatalf("failed to create hostmock: %v", err)
+			}
+
+			client, err := New(Config{HostCall: mock.HostCall})
+			if err != nil {
+				t.Fatalf("New returned error: %v", err)
+			}
+
+			err = client.Validate(tc.query)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("expected %v, got %v", tc.wantErr, err)
+			}
+			if tc.wantMsg != "" {
+				var statusErr *sdk.HostStatusError
+				if !errors.As(err, &statusErr) || statusErr.Code != 400 || statusErr.Message != tc.wantMsg {
+					t.Fatalf("expected status 400 with message %q, got %v", tc.wantMsg, err)
+				}
+			}
+			if got := mock.Count(); got != tc.wantCalls {
+				t.Fatalf("expected %d host calls, got %d", tc.wantCalls, got)
+			}
+		})
+	}
+}
+
+func TestCapabilityOverride(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		name       string
+		capability string
+		want       string
+	}{
+		{name: "default", want: capabilityName},
+		{name: "custom", capability: "custom-sql", want: "custom-sql"},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			mock, err := hostmock.New(hostmock.Config{
+				ExpectedCapability: tc.want,
+				Response: func() []byte {
+					b, _ := (&sdkproto.Status{Code: 200}).MarshalVT()
+					return b
+				},
+			})
+			if err != nil {
+				t.Fatalf("failed to create hostmock: %v", err)
+			}
+
+			client, err := New(Config{HostCall: mock.HostCall, Capability: tc.capability})
+			if err != nil {
+				t.Fatalf("New returned error: %v", err)
+			}
+			if err := client.Ping(); err != nil {
+				t.Fatalf("Ping returned error: %v", err)
+			}
+
+			calls := mock.Calls()
+			if len(calls) != 1 || calls[0].Capability != tc.want {
+				t.Fatalf("expected one call to capability %q, got %+v", tc.want, calls)
+			}
+		})
+	}
+}
+
+func TestClose(t *testing.T) {
+	t.Parallel()
+
+	mock, err := hostmock.New(hostmock.Config{})
+	if err != nil {
+		t.Fatalf("failed to create hostmock: %v", err)
+	}
+
+	var client Client
+	client, err = New(Config{HostCall: mock.HostCall})
+	if err != nil {
+		t.Fatalf("New returned error: %v", err)
+	}
+
+	// Close must be safe to defer unconditionally and to call more than once.
+	for i := range 2 {
+		if err := client.Close(); err != nil {
+			t.Fatalf("Close call %d returned error: %v", i+1, err)
+		}
+	}
+	if mock.Count() != 0 {
+		t.Fatalf("expected Close to make no host calls, got %d", mock.Count())
+	}
+}
+
+func TestZeroValueClient(t *testing.T) {
+	t.Parallel()
+
+	// A client not built with New has no host call; it must fail rather than panic.
+	var client DBClient
+	if _, err := client.Query("SELECT 1"); !errors.Is(err, sdk.ErrNilHostCall) || !errors.Is(err, sdk.ErrHostCall) {
+		t.Fatalf("expected ErrNilHostCall wrapped in ErrHostCall, got %v", err)
+	}
+}
+
+func TestEmptyHostResponse(t *testing.T) {
+	t.Parallel()
+
+	ops := []struct {
+		name string
+		call func(*DBClient) error
+	}{
+		{name: "exec", call: func(c *DBClient) error { _, err := c.Exec("DELETE FROM t"); return err }},
+		{name: "query", call: func(c *DBClient) error { _, err := c.Query("SELECT 1"); return err }},
+		{name: "validate", call: func(c *DBClient) error { return c.Validate("SELECT 1") }},
+		{name: "ping", call: func(c *DBClient) error { return c.Ping() }},
+	}
+
+	payloads := map[string][]byte{"nil": nil, "empty": {}}
+
+	for _, op := range ops {
+		for kind, payload := range payloads {
+			t.Run(op.name+" "+kind, func(t *testing.T) {
+				t.Parallel()
+
+				mock, err := hostmock.New(hostmock.Config{Response: func() []byte { return payload }})
+				if err != nil {
+					t.Fatalf("failed to create hostmock: %v", err)
+				}
+
+				client, err := New(Config{HostCall: mock.HostCall})
+				if err != nil {
+					t.Fatalf("New returned error: %v", err)
+				}
+
+				if err := op.call(client); !errors.Is(err, sdk.ErrHostResponseInvalid) {
+					t.Fatalf("expected %v, got %v", sdk.ErrHostResponseInvalid, err)
+				}
+			})
+		}
+	}
+}
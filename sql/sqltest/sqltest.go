@@ -0,0 +1,39 @@
+/*
+Package sqltest builds canned sql host responses for tests.
+
+Each helper returns the encoded payload the sql client expects from the host,
+ready to return from a hostmock Response or Responses entry or a hand-written
+host call. Every helper takes the status the response carries, built with
+sdktest.OK, sdktest.Partial, or sdktest.Failed, so one helper covers success
+and failure alike. Bare Ping and Validate statuses come from
+sdktest.StatusResponse:
+
+	mock, _ := hostmock.New(hostmock.Config{
+	  Response: func() []byte {
+	    return sqltest.QueryResponse(sdktest.OK(), []string{"id"}, []byte(`[{"id":1}]`))
+	  },
+	})
+*/
+package sqltest
+
+import (
+	sdkproto "github.com/tarmac-project/protobuf-go/sdk"
+	proto "github.com/tarmac-project/protobuf-go/sdk/sql"
+)
+
+// ExecResponse encodes an exec response with the given result metadata.
+func ExecResponse(status *sdkproto.Status, lastInsertID, rowsAffected int64) []byte {
+	b, _ := (&proto.SQLExecResponse{
+		Status:       status,
+		LastInsertId: lastInsertID,
+		RowsAffected: rowsAffected,
+	}).MarshalVT()
+	return b
+}
+
+// QueryResponse encodes a query response. data is the JSON array of row
+// objects the host sends, such as `[{"id":1}]`.
+func QueryResponse(status *sdkproto.Status, columns []string, data []byte) []byte {
+	b, _ := (&proto.SQLQueryResponse{Status: status, Columns: columns, Data: data}).MarshalVT()
+	return b
+}
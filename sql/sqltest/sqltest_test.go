@@ -0,0 +1,60 @@
+package sqltest_test
+
+import (
+	"errors"
+	"testing"
+
+	sdk "github.com/tarmac-project/sdk"
+	"github.com/tarmac-project/sdk/hostmock"
+	"github.com/tarmac-project/sdk/sdktest"
+	"github.com/tarmac-project/sdk/sql"
+	"github.com/tarmac-project/sdk/sql/sqltest"
+)
+
+func TestResponses(t *testing.T) {
+	t.Parallel()
+
+	mock, err := hostmock.New(hostmock.Config{
+		Functions: map[string]hostmock.FunctionConfig{
+			"exec": {Response: func() []byte { return sqltest.ExecResponse(sdktest.OK(), 7, 2) }},
+			"query": {Responses: []func() ([]byte, error){
+				func() ([]byte, error) {
+					return sqltest.QueryResponse(sdktest.OK(), []string{"id"}, []byte(`[{"id":1}]`)), nil
+				},
+				func() ([]byte, error) {
+					return sqltest.QueryResponse(sdktest.Partial("degraded"), []string{"id"}, []byte(`[]`)), nil
+				},
+			}},
+			"validate": {
+				Response: func() []byte { return sdktest.StatusResponse(sdktest.Failed(sdk.StatusBadInput, "syntax")) },
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to create hostmock: %v", err)
+	}
+
+	client, err := sql.New(sql.Config{HostCall: mock.HostCall})
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	res, err := client.Exec("UPDATE t SET v = 1")
+	if err != nil || res.LastInsertID != 7 || res.RowsAffected != 2 {
+		t.Fatalf("Exec: got %+v, %v", res, err)
+	}
+
+	result, err := client.Query("SELECT id FROM t")
+	if err != nil || len(result.Columns) != 1 || string(result.Data) != `[{"id":1}]` {
+		t.Fatalf("Query: got %+v, %v", result, err)
+	}
+
+	if _, err := client.Query("SELECT id FROM t"); !errors.Is(err, sql.ErrPartialResult) {
+		t.Fatalf("Query partial: expected %v, got %v", sql.ErrPartialResult, err)
+	}
+
+	var statusErr *sdk.HostStatusError
+	if err := client.Validate("SELEC"); !errors.As(err, &statusErr) || statusErr.Message != "syntax" {
+		t.Fatalf("Validate: expected host status message %q, got %v", "syntax", err)
+	}
+}
@@ -0,0 +1,44 @@
+package sdk
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Host status codes reported in capability responses.
+const (
+	// StatusOK indicates the host completed the operation.
+	StatusOK = int32(200)
+
+	// StatusPartial indicates the host completed the operation with degraded
+	// results or metadata.
+	StatusPartial = int32(206)
+
+	// StatusBadInput indicates the host rejected the request.
+	StatusBadInput = int32(400)
+
+	// StatusNotFound indicates the requested resource does not exist.
+	StatusNotFound = int32(404)
+
+	// StatusError indicates the host failed while handling the request.
+	StatusError = int32(500)
+)
+
+// StatusToError maps a host status code to an SDK error. Success and partial
+// codes return nil, documented failure codes return a *HostStatusError carrying
+// the code and message, and any other code returns ErrHostResponseInvalid.
+func StatusToError(code int32, message string) error {
+	switch code {
+	case StatusOK, StatusPartial:
+		return nil
+	case StatusBadInput, StatusNotFound, StatusError:
+		return &HostStatusError{Code: code, Message: message}
+	default:
+		return errors.Join(ErrHostResponseInvalid, fmt.Errorf("unexpected host status code %d", code))
+	}
+}
+
+// IsPartial reports whether code signals a partial result.
+func IsPartial(code int32) bool {
+	return code == StatusPartial
+}